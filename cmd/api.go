@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	httputl "net/http/httputil"
+
+	"github.com/HotelsDotCom/flyte/httputil"
+)
+
+// responseError holds an unexpected flyte API response
+type responseError struct {
+	statusCode int
+	status     string
+	dump       []byte
+}
+
+func newResponseError(resp *http.Response) error {
+	dump, err := httputl.DumpResponse(resp, true)
+	if err != nil {
+		return err
+	}
+	return responseError{statusCode: resp.StatusCode, status: resp.Status, dump: dump}
+}
+
+func (e responseError) Error() string {
+	return string(e.dump)
+}
+
+func isNotFound(err error) bool {
+	e, ok := err.(responseError)
+	return ok && e.statusCode == http.StatusNotFound
+}
+
+// getJSON fetches the resource from the url and decodes it into v
+func getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", httputil.MediaTypeJson)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// deleteResource deletes the resource at the url
+func deleteResource(url string) error {
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return newResponseError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,492 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+const (
+	kindFlow      = "flow"
+	kindDatastore = "datastore"
+
+	statusCreated   = "created"
+	statusUpdated   = "updated"
+	statusUnchanged = "unchanged"
+	statusPruned    = "pruned"
+	statusFailed    = "failed"
+	statusSkipped   = "skipped"
+)
+
+// manifestFile in the applied directory describes how its files map to resources
+const manifestFile = "flyte-manifest.yaml"
+
+var argsApply = struct {
+	dir      string
+	prune    bool
+	prefixes []string
+	selector string
+}{}
+
+func newCmdApply() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply -f DIR",
+		Short: "Create or update flows and datastore items from a directory",
+		Long:  longApply,
+		Args:  cobra.NoArgs,
+		RunE:  runApply,
+	}
+
+	cmd.Flags().StringVarP(&argsApply.dir, flagFilename, "f", "", "directory with flow and datastore item files")
+	cmd.MarkFlagRequired(flagFilename)
+
+	cmd.Flags().BoolVar(&argsApply.prune, flagPrune, false, "delete flows and datastore items which are not in the directory")
+	cmd.Flags().StringSliceVar(&argsApply.prefixes, flagPrefix, nil, "prune only resources with names starting with one of the prefixes")
+	cmd.Flags().StringVarP(&argsApply.selector, flagSelector, "l", "", "prune only flows with steps of packs matching the labels, e.g. env=prod,team=ops")
+	return cmd
+}
+
+const longApply = `
+Create or update flows and datastore items from files in a directory and its subdirectories.
+Resources which are the same in the flyte API are left unchanged, so apply can be run
+repeatedly, e.g. from a git repository by CI. Each resource is reported as created, updated
+or unchanged. Flows are updated by deleting and creating them again, when the new flow
+is refused by the flyte API the previous one is created again.
+
+JSON and YAML files with flow name and steps are flows, any other file is a datastore item
+named after the file without its extension. Hidden files and directories are skipped.
+Files with .enc extension encrypted by 'flyte ds encrypt' are decrypted in memory with
+the key set by the --key-file option.
+
+Optional flyte-manifest.yaml in the directory overrides the classification:
+---
+# glob patterns of flow files, relative to the directory
+flows:
+- flows/*.yaml
+# datastore items with their name, description and content type
+datastore:
+- file: ds/env.json
+  name: env
+  description: Environment settings
+  contentType: application/json
+# glob patterns of files to skip
+ignore:
+- README.md
+
+With --prune option flows and datastore items in the flyte API which are not in the directory
+are deleted and reported as pruned. Pruning can be limited to resources with names starting
+with --prefix, and to flows with steps of packs matching --selector labels. Datastore items
+have no labels, so they are never pruned when --selector is set.
+
+With the global --dry-run option the changes are only previewed, every resource is reported
+with its status followed by "(dry run)" and nothing is changed.
+
+Examples:
+  # Apply all flows and datastore items from ./flyte directory
+  flyte apply -f ./flyte
+
+  # Preview which resources named ops-* would be deleted from flyte API
+  flyte apply -f ./flyte --prune --prefix ops- --dry-run
+
+  # Apply and print the results as json
+  flyte apply -f ./flyte -o json
+`
+
+// applyManifest is the content of the manifest file
+type applyManifest struct {
+	Flows     []string       `json:"flows,omitempty"`
+	Datastore []manifestItem `json:"datastore,omitempty"`
+	Ignore    []string       `json:"ignore,omitempty"`
+}
+
+type manifestItem struct {
+	File        string `json:"file"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// resource is a flow or a datastore item read from a file
+type resource struct {
+	kind        string
+	name        string
+	filename    string
+	data        []byte
+	contentType string
+	description string
+}
+
+func runApply(c *cobra.Command, args []string) error {
+	if err := checkOutput(); err != nil {
+		return err
+	}
+	selector, err := parseSelector(argsApply.selector)
+	if err != nil {
+		return err
+	}
+
+	resources, err := readResources(argsApply.dir)
+	if err != nil {
+		return err
+	}
+
+	items, err := listDsItems(apiURL())
+	if err != nil {
+		return err
+	}
+
+	var results []uploadResult
+	failed := 0
+	for _, r := range resources {
+		result, err := applyResource(apiURL(), r, items, isDryRun())
+		if err != nil {
+			fmt.Fprintf(c.OutOrStderr(), "cannot apply %s/%s from %s\n%s\n", r.kind, r.name, r.filename, err)
+			failed++
+			continue
+		}
+		results = append(results, result)
+	}
+
+	total := len(resources)
+	if argsApply.prune {
+		pruned, errs := prune(apiURL(), resources, items, pruneFilter{prefixes: argsApply.prefixes, selector: selector}, isDryRun())
+		for _, err := range errs {
+			fmt.Fprintln(c.OutOrStderr(), err)
+		}
+		results = append(results, pruned...)
+		failed += len(errs)
+		total += len(pruned) + len(errs)
+	}
+
+	if err := printUploadResults(c, results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("cannot apply %d of %d resource(s)", failed, total)
+	}
+	return nil
+}
+
+// applyResource creates or updates the resource unless it is the same in flyte API, nothing is changed on dry run
+func applyResource(apiURL string, r resource, items map[string]dsSummary, dryRun bool) (uploadResult, error) {
+	url := flowURL(apiURL, r.name)
+	if r.kind == kindDatastore {
+		url = dsItemURL(apiURL, r.name)
+	}
+
+	remote, err := fetchRemote(apiURL, r, items)
+	if err != nil {
+		return uploadResult{}, err
+	}
+
+	status := statusCreated
+	if remote.exists {
+		status = statusUpdated
+		if remote.rendered == renderLocal(r) {
+			status = statusUnchanged
+		}
+	}
+	if dryRun || status == statusUnchanged {
+		return uploadResult{Kind: r.kind, Name: r.name, Location: url, Status: dryRunStatus(status, dryRun)}, nil
+	}
+
+	if r.kind == kindFlow {
+		return applyFlow(apiURL, r, remote.flow)
+	}
+	return applyDs(apiURL, r)
+}
+
+func dryRunStatus(status string, dryRun bool) string {
+	if dryRun {
+		return status + " (dry run)"
+	}
+	return status
+}
+
+// readResources reads flows and datastore items from the directory, datastore items go first
+func readResources(dir string) ([]resource, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("cannot apply: %s is not a directory", dir)
+	}
+
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []resource
+	for _, filename := range files {
+		rel, err := relPath(dir, filename)
+		if err != nil {
+			return nil, err
+		}
+		if rel == manifestFile || matchAny(manifest.Ignore, rel) {
+			continue
+		}
+
+		r, err := manifest.resource(filename, rel)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, r)
+	}
+
+	if err := checkDuplicates(resources); err != nil {
+		return nil, err
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].kind != resources[j].kind {
+			return resources[i].kind == kindDatastore
+		}
+		return resources[i].name < resources[j].name
+	})
+	return resources, nil
+}
+
+// listFiles lists files in the directory and its subdirectories, hidden files and directories are skipped
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filename != dir && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			files = append(files, filename)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// relPath is the slash separated path of the file relative to the directory, as used by the manifest
+func relPath(dir, filename string) (string, error) {
+	rel, err := filepath.Rel(dir, filename)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// readManifest reads the manifest file from the directory, missing file is an empty manifest
+func readManifest(dir string) (*applyManifest, error) {
+	manifest, err := readManifestFile(filepath.Join(dir, manifestFile))
+	if os.IsNotExist(err) {
+		return &applyManifest{}, nil
+	}
+	return manifest, err
+}
+
+func readManifestFile(filename string) (*applyManifest, error) {
+	data, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &applyManifest{}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %v", filepath.Base(filename), err)
+	}
+	return manifest, nil
+}
+
+// resource classifies the file as a flow or a datastore item
+func (m *applyManifest) resource(filename, rel string) (resource, error) {
+	data, err := readDsValue(filename)
+	if err != nil {
+		return resource{}, err
+	}
+	plain := plainFilename(filename)
+
+	r := resource{filename: filename, data: data}
+	for _, item := range m.Datastore {
+		if path.Clean(item.File) == rel {
+			r.kind = kindDatastore
+			r.name = item.Name
+			r.description = item.Description
+			r.contentType = item.ContentType
+		}
+	}
+
+	if r.kind == "" && (matchAny(m.Flows, rel) || isFlow(plain, data)) {
+		r.kind = kindFlow
+		r.name = flowName(data)
+		// flow files picked by the manifest may have no extension
+		r.contentType = getContentType(detectExt(plain, data))
+		if r.name == "" {
+			return resource{}, fmt.Errorf("cannot read flow from %s: name is missing", filename)
+		}
+		return r, nil
+	}
+
+	r.kind = kindDatastore
+	if r.name == "" {
+		base := filepath.Base(plain)
+		r.name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if r.contentType == "" {
+		r.contentType = getContentType(detectExt(plain, data))
+	}
+	return r, nil
+}
+
+// isFlow is true for JSON or YAML files with flow name and steps
+func isFlow(filename string, data []byte) bool {
+	switch detectExt(filename, data) {
+	case ".json", ".yaml", ".yml":
+	default:
+		return false
+	}
+
+	flow := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &flow); err != nil {
+		return false
+	}
+	_, steps := flow["steps"]
+	_, name := flow["name"]
+	return steps && name
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(path.Clean(p), rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDuplicates(resources []resource) error {
+	files := map[string]string{}
+	for _, r := range resources {
+		key := r.kind + "/" + r.name
+		if f, ok := files[key]; ok {
+			return fmt.Errorf("%s is defined in both %s and %s", key, f, r.filename)
+		}
+		files[key] = r.filename
+	}
+	return nil
+}
+
+// applyFlow creates the flow, the previous flow is replaced unless it is nil
+func applyFlow(apiURL string, r resource, previous map[string]interface{}) (uploadResult, error) {
+	if previous != nil {
+		resp, err := recreateFlow(apiURL, r.name, r.contentType, r.data, previous)
+		if err != nil {
+			return uploadResult{}, err
+		}
+		defer resp.Body.Close()
+
+		result := newUploadResult(kindFlow, r.name, resp)
+		result.Status = statusUpdated
+		return result, nil
+	}
+
+	resp, err := createFlow(apiURL, r.contentType, r.data)
+	if err != nil {
+		return uploadResult{}, err
+	}
+	defer resp.Body.Close()
+	return newUploadResult(kindFlow, r.name, resp), nil
+}
+
+// createFlow posts the flow to flyte API, responses other than 201 Created are returned as responseError
+func createFlow(apiURL, contentType string, data []byte) (*http.Response, error) {
+	resp, err := client.Post(flowsURL(apiURL), contentType, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		defer resp.Body.Close()
+		return nil, newResponseError(resp)
+	}
+	return resp, nil
+}
+
+// recreateFlow replaces the flow by deleting and creating it again, flyte API cannot update flows.
+// When the new definition is refused the previous one is created again, so the flow is not lost.
+func recreateFlow(apiURL, name, contentType string, data []byte, previous map[string]interface{}) (*http.Response, error) {
+	if err := deleteResource(flowURL(apiURL, name)); err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resp, err := createFlow(apiURL, contentType, data)
+	if err == nil {
+		return resp, nil
+	}
+
+	original, merr := json.Marshal(previous)
+	if merr != nil {
+		return nil, fmt.Errorf("flow was deleted but cannot be created again\n%s\nprevious flow cannot be restored: %v", err, merr)
+	}
+	restored, rerr := createFlow(apiURL, httputil.MediaTypeJson, original)
+	if rerr != nil {
+		return nil, fmt.Errorf("flow was deleted but cannot be created again\n%s\nprevious flow cannot be restored\n%s", err, rerr)
+	}
+	restored.Body.Close()
+	return nil, fmt.Errorf("flow cannot be replaced, previous flow is restored\n%s", err)
+}
+
+// applyDs uploads the datastore item
+func applyDs(apiURL string, r resource) (uploadResult, error) {
+	req, err := newDsRequest(apiURL, dsItem{
+		name:        r.name,
+		description: r.description,
+		contentType: r.contentType,
+		filename:    r.filename,
+		value:       r.data,
+	})
+	if err != nil {
+		return uploadResult{}, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return uploadResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return uploadResult{}, newResponseError(resp)
+	}
+	return newUploadResult(kindDatastore, r.name, resp), nil
+}
+
+// listDsItems returns datastore items in flyte API by their names
+func listDsItems(apiURL string) (map[string]dsSummary, error) {
+	var list dsList
+	if err := getJSON(dsURL(apiURL), &list); err != nil {
+		return nil, fmt.Errorf("cannot list datastore items\n%s", err)
+	}
+
+	items := map[string]dsSummary{}
+	for _, i := range list.Items {
+		items[i.Name] = i
+	}
+	return items, nil
+}
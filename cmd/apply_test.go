@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply_ShouldCreateFlowsAndDatastoreItems(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env created\n"+
+		"datastore/hello created\n"+
+		"flow/deploy created\n"+
+		"flow/status created\n", output)
+
+	assert.Equal(t, `{"channel":"123"}`+"\n", string(api.ds["env"].value))
+	assert.Equal(t, "Slack environment", api.ds["env"].description)
+	assert.Equal(t, "application/json", api.ds["env"].contentType)
+	assert.Equal(t, "application/x-sh", api.ds["hello"].contentType)
+	assert.Equal(t, "Replies with the bot status", api.flows["status"]["description"])
+}
+
+func TestApply_ShouldLeaveSameResourcesUnchanged(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	_, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL)
+	require.NoError(t, err)
+	api.requests = nil
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env unchanged\n"+
+		"datastore/hello unchanged\n"+
+		"flow/deploy unchanged\n"+
+		"flow/status unchanged\n", output)
+	for _, r := range api.requests {
+		assert.True(t, strings.HasPrefix(r, "GET "), r)
+	}
+}
+
+func TestApply_ShouldUpdateChangedResources(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["status"] = map[string]interface{}{"name": "status", "steps": []interface{}{}}
+	api.ds["env"] = fakeDsItem{value: []byte(`{"channel":"123"}` + "\n"), contentType: "application/json", description: "old"}
+	api.ds["hello"] = fakeDsItem{value: []byte("#!/bin/sh\necho hello\n"), contentType: "application/x-sh"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL, "-o", "wide")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "KIND       NAME    STATUS     LOCATION\n"+
+		"datastore  env     updated    "+ts.URL+flytepath.DatastorePath+"/env\n"+
+		"datastore  hello   unchanged  "+ts.URL+flytepath.DatastorePath+"/hello\n"+
+		"flow       deploy  created    "+ts.URL+flytepath.FlowsPath+"/deploy\n"+
+		"flow       status  updated    "+ts.URL+flytepath.FlowsPath+"/status\n", output)
+	assert.Contains(t, api.requests, "DELETE "+flytepath.FlowsPath+"/status")
+	assert.Equal(t, "Slack environment", api.ds["env"].description)
+}
+
+func TestApply_ShouldReportFailedResourcesAndContinue(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.failPut = "hello"
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL, "-o", "name")
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot apply 1 of 4 resource(s)", err.Error())
+	assert.Contains(t, output, "cannot apply datastore/hello from testdata/apply/ds/hello.sh\nHTTP/1.1 500 Internal Server Error")
+	assert.Contains(t, output, "datastore/env\nflow/deploy\nflow/status\n")
+}
+
+func TestApply_ShouldRestorePreviousFlowWhenNewOneIsRefused(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["status"] = map[string]interface{}{"name": "status", "description": "old", "steps": []interface{}{}}
+	api.rejectFlow = "Replies with the bot status"
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot apply 1 of 4 resource(s)", err.Error())
+	assert.Contains(t, output, "cannot apply flow/status from testdata/apply/flows/status.yaml\n"+
+		"flow cannot be replaced, previous flow is restored\nHTTP/1.1 400 Bad Request")
+	assert.Equal(t, map[string]interface{}{"name": "status", "description": "old", "steps": []interface{}{}}, api.flows["status"])
+}
+
+func TestApply_ShouldClassifyFilesWithoutManifest(t *testing.T) {
+	//when
+	resources, err := readResources("./testdata/apply/flows")
+
+	//then
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, resource{kind: kindFlow, name: "deploy", filename: "testdata/apply/flows/deploy.json",
+		data: resources[0].data, contentType: "application/json"}, resources[0])
+	assert.Equal(t, kindFlow, resources[1].kind)
+	assert.Equal(t, "status", resources[1].name)
+}
+
+func TestApply_ShouldFailForFile(t *testing.T) {
+	_, err := executeCommand("apply", "-f", "./testdata/my-flow.yaml", "--url", "http://localhost:1")
+
+	require.Error(t, err)
+	assert.Equal(t, "cannot apply: ./testdata/my-flow.yaml is not a directory", err.Error())
+}
+
+type fakeDsItem struct {
+	value       []byte
+	contentType string
+	description string
+}
+
+// fakeAPI keeps flows and datastore items in memory and records all requests
+type fakeAPI struct {
+	mu       sync.Mutex
+	flows    map[string]map[string]interface{}
+	ds       map[string]fakeDsItem
+	packs    []pack
+	requests []string
+	failPut  string
+	// rejectFlow is the description of flows refused with 400 Bad Request
+	rejectFlow string
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{flows: map[string]map[string]interface{}{}, ds: map[string]fakeDsItem{}}
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = append(a.requests, r.Method+" "+r.URL.Path)
+
+	switch {
+	case r.URL.Path == flytepath.FlowsPath:
+		a.serveFlows(w, r)
+	case strings.HasPrefix(r.URL.Path, flytepath.FlowsPath+"/"):
+		a.serveFlow(w, r, strings.TrimPrefix(r.URL.Path, flytepath.FlowsPath+"/"))
+	case r.URL.Path == flytepath.DatastorePath:
+		a.serveDatastore(w, r)
+	case strings.HasPrefix(r.URL.Path, flytepath.DatastorePath+"/"):
+		a.serveDsItem(w, r, strings.TrimPrefix(r.URL.Path, flytepath.DatastorePath+"/"))
+	case r.URL.Path == flytepath.PacksPath:
+		json.NewEncoder(w).Encode(packList{Packs: a.packs})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (a *fakeAPI) serveFlows(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		var names []string
+		for name := range a.flows {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		list := flowList{}
+		for _, name := range names {
+			description, _ := a.flows[name]["description"].(string)
+			list.Flows = append(list.Flows, flowSummary{Name: name, Description: description})
+		}
+		json.NewEncoder(w).Encode(list)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	flow := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &flow); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	name, _ := flow["name"].(string)
+	if description, _ := flow["description"].(string); description != "" && description == a.rejectFlow {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if _, ok := a.flows[name]; ok {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	a.flows[name] = flow
+	w.Header().Set("Location", flytepath.FlowsPath+"/"+name)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (a *fakeAPI) serveFlow(w http.ResponseWriter, r *http.Request, name string) {
+	flow, ok := a.flows[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		delete(a.flows, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		withLinks := map[string]interface{}{"links": []interface{}{map[string]string{"href": "http://flyte/" + name}}}
+		for k, v := range flow {
+			withLinks[k] = v
+		}
+		json.NewEncoder(w).Encode(withLinks)
+	}
+}
+
+func (a *fakeAPI) serveDatastore(w http.ResponseWriter, r *http.Request) {
+	var names []string
+	for name := range a.ds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := dsList{}
+	for _, name := range names {
+		item := a.ds[name]
+		list.Items = append(list.Items, dsSummary{Name: name, ContentType: item.contentType, Description: item.description})
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+func (a *fakeAPI) serveDsItem(w http.ResponseWriter, r *http.Request, name string) {
+	item, ok := a.ds[name]
+	switch r.Method {
+	case http.MethodPut:
+		if name == a.failPut {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f, h, err := r.FormFile("value")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+		value, _ := ioutil.ReadAll(f)
+		a.ds[name] = fakeDsItem{value: value, contentType: h.Header.Get("Content-Type"), description: r.FormValue("description")}
+		if ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(a.ds, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", item.contentType)
+		fmt.Fprintf(w, "%s", item.value)
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/spf13/viper"
+)
+
+// authConfig holds credentials used for every request to flyte API
+type authConfig struct {
+	Token             string `json:"token,omitempty"`
+	Username          string `json:"username,omitempty"`
+	Password          string `json:"password,omitempty"`
+	ClientCertificate string `json:"client-certificate,omitempty"`
+	ClientKey         string `json:"client-key,omitempty"`
+}
+
+// resolveAuth takes credentials from the options or environment variables and then from the active context.
+// A token or a username from the options replaces any other type of the context's credentials,
+// and client certificate and key from the options replace both of the context's ones.
+func resolveAuth() authConfig {
+	var auth authConfig
+	if activeContext.Auth != nil {
+		auth = *activeContext.Auth
+	}
+
+	if viper.GetString(flagToken) != "" || viper.GetString(flagUsername) != "" {
+		auth.Token, auth.Username, auth.Password = "", "", ""
+	}
+	if viper.GetString(flagClientCertificate) != "" || viper.GetString(flagClientKey) != "" {
+		auth.ClientCertificate, auth.ClientKey = "", ""
+	}
+
+	override(&auth.Token, flagToken)
+	override(&auth.Username, flagUsername)
+	override(&auth.Password, flagPassword)
+	override(&auth.ClientCertificate, flagClientCertificate)
+	override(&auth.ClientKey, flagClientKey)
+	return auth
+}
+
+// scheme is the authorization scheme used for the credentials, empty when there are none
+func (a authConfig) scheme() string {
+	switch {
+	case a.Token != "":
+		return "Bearer"
+	case a.Username != "":
+		return "Basic"
+	}
+	return ""
+}
+
+func override(value *string, key string) {
+	if v := viper.GetString(key); v != "" {
+		*value = v
+	}
+}
+
+// authTransport adds authorization header to every request
+type authTransport struct {
+	auth authConfig
+	base http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authorization := ""
+	switch {
+	case t.auth.Token != "":
+		authorization = "Bearer " + t.auth.Token
+	case t.auth.Username != "":
+		credentials := t.auth.Username + ":" + t.auth.Password
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	default:
+		return t.base.RoundTrip(req)
+	}
+
+	// round trippers must not modify the request
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+	r.Header.Set("Authorization", authorization)
+	return t.base.RoundTrip(r)
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth_ShouldSendBearerTokenFromOption(t *testing.T) {
+	//given
+	var authorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"flows":[]}`)
+	}))
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL, "--token", "secret")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", authorization)
+}
+
+func TestAuth_ShouldSendBasicAuthFromEnv(t *testing.T) {
+	//given
+	var username, password string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ = r.BasicAuth()
+		fmt.Fprint(w, `{"flows":[]}`)
+	}))
+	defer ts.Close()
+
+	os.Setenv("FLYTE_USERNAME", "bob")
+	os.Setenv("FLYTE_PASSWORD", "pass")
+	defer os.Unsetenv("FLYTE_USERNAME")
+	defer os.Unsetenv("FLYTE_PASSWORD")
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "bob", username)
+	assert.Equal(t, "pass", password)
+}
+
+func TestAuth_ShouldSendTokenFromContextWithDatastoreLookup(t *testing.T) {
+	//given
+	var authorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "Hello")
+	}))
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n  auth:\n    token: ctx-token\n", ts.URL))
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("test", "-f", "./testdata/step-ds-non-json.yaml", "--config", config)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer ctx-token", authorization)
+}
+
+func TestAuth_ShouldOverrideContextTokenWithOption(t *testing.T) {
+	//given
+	var authorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"flows":[]}`)
+	}))
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n  auth:\n    token: ctx-token\n", ts.URL))
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("get", "flows", "--config", config, "--token", "option-token")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer option-token", authorization)
+}
+
+func TestAuth_ShouldReplaceContextBasicAuthWithTokenOption(t *testing.T) {
+	//given
+	var authorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"flows":[]}`)
+	}))
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n  auth:\n    username: bob\n    password: pass\n", ts.URL))
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("get", "flows", "--config", config, "--token", "option-token")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer option-token", authorization)
+}
+
+func TestAuth_ShouldReplaceContextTokenWithBasicAuthFromEnv(t *testing.T) {
+	//given
+	var username string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, _ = r.BasicAuth()
+		fmt.Fprint(w, `{"flows":[]}`)
+	}))
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n  auth:\n    token: ctx-token\n", ts.URL))
+	defer cleanup()
+	os.Setenv("FLYTE_USERNAME", "bob")
+	defer os.Unsetenv("FLYTE_USERNAME")
+
+	//when
+	_, err := executeCommand("get", "flows", "--config", config)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "bob", username)
+}
+
+func TestAuth_ShouldFailForTokenAndBasicAuth(t *testing.T) {
+	_, err := executeCommand("get", "flows", "--url", "http://localhost:1", "--token", "secret", "--username", "bob")
+
+	require.Error(t, err)
+	assert.Equal(t, "cannot use both bearer token and basic auth, choose one of them", err.Error())
+}
+
+func TestAuth_ShouldFailForClientCertificateWithoutKey(t *testing.T) {
+	_, err := executeCommand("get", "flows", "--url", "http://localhost:1", "--client-certificate", "./testdata/tls/client.crt")
+
+	require.Error(t, err)
+	assert.Equal(t, "client certificate and client key must be set together", err.Error())
+}
+
+func TestAuth_ShouldFailForInvalidClientCertificate(t *testing.T) {
+	_, err := executeCommand("get", "flows", "--url", "http://localhost:1",
+		"--client-certificate", "./testdata/tls/client.crt", "--client-key", "./testdata/tls/ca.crt")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot load client certificate: ")
+}
+
+func TestAuth_ShouldLoadClientCertificate(t *testing.T) {
+	//given
+	_, err := executeCommand("version", "--client-certificate", "./testdata/tls/client.crt", "--client-key", "./testdata/tls/client.key")
+	require.NoError(t, err)
+
+	//then
+	transport, ok := client.Transport.(*authTransport)
+	require.True(t, ok)
+	retry, ok := transport.base.(*retryTransport)
+	require.True(t, ok)
+	base, ok := retry.base.(*tlsTransport)
+	require.True(t, ok)
+	assert.Len(t, base.base.TLSClientConfig.Certificates, 1)
+}
+
+func TestAuth_ShouldSaveCredentialsInContext(t *testing.T) {
+	//given
+	config, cleanup := tempConfig(t, "contexts:\n- name: dev\n  url: http://dev:8080\n")
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("config", "set-context", "dev", "--username", "bob", "--password", "pass", "--config", config)
+	require.NoError(t, err)
+
+	//then
+	data, err := ioutil.ReadFile(config)
+	require.NoError(t, err)
+	assert.Equal(t, "contexts:\n- auth:\n    password: pass\n    username: bob\n  name: dev\n  url: http://dev:8080\n", string(data))
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// backupVersion is the version of the backup layout, restore refuses backups of other versions
+	backupVersion = 1
+	// backupManifestFile in the backup lists its flows, datastore items and packs
+	backupManifestFile = "flyte-backup.yaml"
+
+	backupFlowsDir  = "flows"
+	backupPacksFile = "packs.yaml"
+)
+
+var argsBackup = struct {
+	output string
+}{}
+
+// backupManifest is the content of the backup manifest file
+type backupManifest struct {
+	Version   int            `json:"version"`
+	Created   string         `json:"created"`
+	URL       string         `json:"url"`
+	Flows     []backupFlow   `json:"flows,omitempty"`
+	Datastore []manifestItem `json:"datastore,omitempty"`
+	Packs     string         `json:"packs,omitempty"`
+}
+
+type backupFlow struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+}
+
+func newCmdBackup() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup -o FILENAME",
+		Short: "Back up flows, datastore items and packs to an archive or a directory",
+		Long:  longBackup,
+		Args:  cobra.NoArgs,
+		RunE:  runBackup,
+	}
+
+	cmd.Flags().StringVarP(&argsBackup.output, flagOutput, "o", "", "tar.gz archive or directory to back up to, it overrides the global output format option")
+	cmd.MarkFlagRequired(flagOutput)
+	return cmd
+}
+
+const longBackup = `
+Back up everything which can be read from a flyte API: flows, datastore items with their
+descriptions and content types, and metadata of registered packs. The backup is saved to
+a tar.gz archive when the filename ends with .tar.gz or .tgz, otherwise to a directory.
+
+Flows are saved to flows/NAME.yaml files, datastore item values to ds/NAME files and packs
+to packs.yaml. Everything is listed in flyte-backup.yaml with the version of the backup.
+Flows and datastore items can be restored by 'flyte restore', packs are registered by
+the packs themselves so they are saved only for reference.
+
+Examples:
+  # Back up prod flyte API to an archive
+  flyte backup -o ./prod.tar.gz --context prod
+`
+
+func runBackup(c *cobra.Command, args []string) error {
+	var flows flowList
+	if err := getJSON(flowsURL(apiURL()), &flows); err != nil {
+		return fmt.Errorf("cannot list flows\n%s", err)
+	}
+	var items dsList
+	if err := getJSON(dsURL(apiURL()), &items); err != nil {
+		return fmt.Errorf("cannot list datastore items\n%s", err)
+	}
+	packs, err := listPacks(apiURL())
+	if err != nil {
+		return err
+	}
+	sort.Slice(flows.Flows, func(i, j int) bool {
+		return flows.Flows[i].Name < flows.Flows[j].Name
+	})
+	sort.Slice(items.Items, func(i, j int) bool {
+		return items.Items[i].Name < items.Items[j].Name
+	})
+
+	w, err := newArchiveWriter(argsBackup.output)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	manifest := backupManifest{
+		Version: backupVersion,
+		Created: time.Now().UTC().Format(time.RFC3339),
+		URL:     apiURL(),
+		Packs:   backupPacksFile,
+	}
+	if manifest.Flows, err = backupFlows(w, apiURL(), flows.Flows); err != nil {
+		return err
+	}
+	if manifest.Datastore, err = exportDs(w, apiURL(), items.Items, dsExportDir); err != nil {
+		return err
+	}
+	if err := addYAML(w, backupPacksFile, packs); err != nil {
+		return err
+	}
+	if err := addYAML(w, backupManifestFile, manifest); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.OutOrStdout(), "%d flow(s), %d datastore item(s) and %d pack(s) backed up to %s\n",
+		len(manifest.Flows), len(manifest.Datastore), len(packs), argsBackup.output)
+	return err
+}
+
+// backupFlows saves the flows without API links as yaml files, it returns the saved flows
+func backupFlows(w archiveWriter, apiURL string, flows []flowSummary) ([]backupFlow, error) {
+	var saved []backupFlow
+	for _, f := range flows {
+		flow, err := fetchFlow(flowURL(apiURL, f.Name))
+		if err != nil {
+			return nil, fmt.Errorf("cannot back up flow/%s\n%s", f.Name, err)
+		}
+		if flow == nil {
+			// the flow was deleted after it was listed
+			continue
+		}
+
+		file := backupFlowsDir + "/" + f.Name + ".yaml"
+		if err := addYAML(w, file, flow); err != nil {
+			return nil, err
+		}
+		saved = append(saved, backupFlow{Name: f.Name, File: file})
+	}
+	return saved, nil
+}
+
+func addYAML(w archiveWriter, name string, v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.add(name, data)
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBackupAPI() *fakeAPI {
+	api := newFakeAPI()
+	api.flows["status"] = map[string]interface{}{"name": "status", "description": "Replies with the bot status", "steps": []interface{}{
+		map[string]interface{}{"id": "status", "event": map[string]interface{}{"packName": "Slack", "name": "ReceivedMessage"}},
+	}}
+	api.ds["env"] = fakeDsItem{value: []byte(`{"channel":"123"}`), contentType: httputil.MediaTypeJson, description: "Slack environment"}
+	api.packs = []pack{{ID: "Slack", Name: "Slack", Status: "ok"}}
+	return api
+}
+
+func TestBackup_ShouldBackUpAndRestoreFlowsAndDatastoreItems(t *testing.T) {
+	//given
+	prod := newBackupAPI()
+	prodServer := httptest.NewServer(prod)
+	defer prodServer.Close()
+
+	staging := newFakeAPI()
+	stagingServer := httptest.NewServer(staging)
+	defer stagingServer.Close()
+
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "prod.tar.gz")
+
+	//when
+	backedUp, err := executeCommand("backup", "-o", archive, "--url", prodServer.URL)
+	require.NoError(t, err)
+	restored, err := executeCommand("restore", "-f", archive, "--url", stagingServer.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, "1 flow(s), 1 datastore item(s) and 1 pack(s) backed up to "+archive+"\n", backedUp)
+	assert.Equal(t, "KIND       NAME    STATUS   LOCATION\n"+
+		"datastore  env     created  "+stagingServer.URL+flytepath.DatastorePath+"/env\n"+
+		"flow       status  created  "+stagingServer.URL+flytepath.FlowsPath+"/status\n", restored)
+	assert.Equal(t, prod.ds, staging.ds)
+	assert.Equal(t, renderValue(prod.flows), renderValue(staging.flows))
+}
+
+func TestBackup_ShouldSaveVersionedManifestAndPacks(t *testing.T) {
+	//given
+	api := newBackupAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	//when
+	_, err = executeCommand("backup", "-o", dir, "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	manifest, err := ioutil.ReadFile(filepath.Join(dir, backupManifestFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(manifest), "version: 1\n")
+	assert.Contains(t, string(manifest), "flows:\n- file: flows/status.yaml\n  name: status\n")
+	assert.Contains(t, string(manifest), "packs: packs.yaml\n")
+	assert.Contains(t, string(manifest), "url: "+ts.URL+"\n")
+
+	packs, err := ioutil.ReadFile(filepath.Join(dir, "packs.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "- id: Slack\n  name: Slack\n  status: ok\n", string(packs))
+
+	flow, err := ioutil.ReadFile(filepath.Join(dir, "flows", "status.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(flow), "links")
+}
+
+func TestRestore_ShouldPrintPlanOnDryRun(t *testing.T) {
+	//given
+	dir := backUp(t)
+	defer os.RemoveAll(dir)
+
+	api := newFakeAPI()
+	api.ds["env"] = fakeDsItem{value: []byte("old"), contentType: "text/plain"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("restore", "-f", dir, "--url", ts.URL, "--conflict", "overwrite", "--dry-run", "-o", "wide")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "KIND       NAME    STATUS             LOCATION\n"+
+		"datastore  env     updated (dry run)  "+ts.URL+flytepath.DatastorePath+"/env\n"+
+		"flow       status  created (dry run)  "+ts.URL+flytepath.FlowsPath+"/status\n", output)
+	for _, r := range api.requests {
+		assert.True(t, strings.HasPrefix(r, "GET "), r)
+	}
+}
+
+func TestRestore_ShouldSkipExistingResources(t *testing.T) {
+	//given
+	dir := backUp(t)
+	defer os.RemoveAll(dir)
+
+	api := newFakeAPI()
+	api.flows["status"] = map[string]interface{}{"name": "status"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("restore", "-f", dir, "--url", ts.URL, "--conflict", "skip", "-o", "name")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env\nflow/status\n", output)
+	assert.NotContains(t, api.flows["status"], "steps")
+	assert.Contains(t, api.ds, "env")
+}
+
+func TestRestore_ShouldReportDatastoreItemWhichCannotBeRestored(t *testing.T) {
+	//given
+	dir := backUp(t)
+	defer os.RemoveAll(dir)
+
+	api := newFakeAPI()
+	api.failPut = "env"
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("restore", "-f", dir, "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, output, "cannot restore datastore/env\nHTTP/1.1 500 Internal Server Error")
+	assert.Contains(t, output, "datastore  env     failed   \n")
+	assert.Contains(t, api.flows, "status")
+}
+
+func TestRestore_ShouldNotRestoreAnythingWhenResourcesExist(t *testing.T) {
+	//given
+	dir := backUp(t)
+	defer os.RemoveAll(dir)
+
+	api := newFakeAPI()
+	api.flows["status"] = map[string]interface{}{"name": "status"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("restore", "-f", dir, "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot restore: resource(s) already exist: flow/status, use --conflict skip or overwrite", err.Error())
+	assert.Empty(t, api.ds)
+}
+
+func TestRestore_ShouldFailForUnsupportedVersion(t *testing.T) {
+	//given
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, backupManifestFile), []byte("version: 2\n"), 0644))
+
+	//when
+	_, err = executeCommand("restore", "-f", dir, "--url", "http://localhost:1")
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot restore: unsupported backup version 2, it must be 1", err.Error())
+}
+
+// backUp backs up the backup API to a temporary directory
+func backUp(t *testing.T) string {
+	ts := httptest.NewServer(newBackupAPI())
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	_, err = executeCommand("backup", "-o", dir, "--url", ts.URL)
+	require.NoError(t, err)
+	return dir
+}
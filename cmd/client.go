@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// retryBackoff is the wait before the first retry, it doubles with every next retry
+var retryBackoff = 500 * time.Millisecond
+
+const maxRetryBackoff = 30 * time.Second
+
+const envRetries = "FLYTE_RETRIES"
+
+// configureClient sets up the client timeout, retries and the transport to verify flyte API certificate
+// and authenticate every request
+func configureClient(c *cobra.Command) error {
+	if timeout := viper.GetString(flagTimeout); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %v", err)
+		}
+		client.Timeout = d
+	}
+
+	// --retries 0 overrides context's retries too
+	retries := activeContext.Retries
+	if _, env := os.LookupEnv(envRetries); env || c.Flags().Changed(flagRetries) {
+		retries = viper.GetInt(flagRetries)
+	}
+	if retries < 0 {
+		return fmt.Errorf("invalid retries: %d must not be negative", retries)
+	}
+
+	auth := resolveAuth()
+	if auth.Token != "" && auth.Username != "" {
+		return errors.New("cannot use both bearer token and basic auth, choose one of them")
+	}
+	if (auth.ClientCertificate == "") != (auth.ClientKey == "") {
+		return errors.New("client certificate and client key must be set together")
+	}
+
+	config, err := newTLSConfig(resolveTLS(c), auth)
+	if err != nil {
+		return err
+	}
+	transport := &tlsTransport{base: &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: config,
+	}}
+
+	client.Transport = &authTransport{
+		auth: auth,
+		base: &retryTransport{retries: retries, base: transport},
+	}
+	return nil
+}
+
+// retryTransport retries idempotent requests failed by network errors or temporarily unavailable API
+// with exponential backoff and jitter, Retry-After header of the response is honored
+type retryTransport struct {
+	retries int
+	base    http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.retries == 0 || !isIdempotent(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt == t.retries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait := backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp); ok {
+				wait = d
+			}
+			resp.Body.Close()
+		}
+
+		if err := sleep(req, wait); err != nil {
+			return nil, err
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isIdempotent is true for requests which can be safely sent again, datastore items are uploaded by PUT
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut:
+		return req.Body == nil || req.GetBody != nil
+	}
+	return false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		// certificate errors do not go away
+		return certificateCause(err) == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff is exponential with jitter, between a half and the whole of the doubled wait
+func backoff(attempt int) time.Duration {
+	d := retryBackoff << uint(attempt)
+	if d <= 0 || d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter reads the wait from Retry-After header of 429 and 503 responses, either in seconds or a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		d := time.Until(date)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep waits unless the request is cancelled by the client timeout
+func sleep(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-req.Cancel:
+		return errors.New("request cancelled while waiting to retry")
+	}
+}
+
+// rewind copies the request with a fresh body so it can be sent again
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := new(http.Request)
+	*r = *req
+	r.Body = body
+	return r, nil
+}
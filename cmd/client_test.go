@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// tests must not wait for the real backoff
+	retryBackoff = time.Millisecond
+}
+
+func TestClient_ShouldRetryGetHonoringRetryAfter(t *testing.T) {
+	//given
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"flows":[{"name":"my-flow"}]}`)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "flows", "--url", ts.URL, "--retries", "3")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, "NAME     DESCRIPTION\nmy-flow  \n", output)
+}
+
+func TestClient_ShouldGiveUpAfterRetries(t *testing.T) {
+	//given
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL, "--retries", "2")
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503 Service Unavailable")
+	assert.Equal(t, 3, attempts)
+}
+
+func TestClient_ShouldNotRetryByDefault(t *testing.T) {
+	//given
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClient_ShouldRetryDatastoreUploadWithTheSameBody(t *testing.T) {
+	//given
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, _, err := r.FormFile("value")
+		require.NoError(t, err)
+		defer f.Close()
+		b, err := ioutil.ReadAll(f)
+		require.NoError(t, err)
+		bodies = append(bodies, string(b))
+
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("upload", "ds", "-f", "./testdata/env.json", "--url", ts.URL, "--retries", "1")
+
+	//then
+	require.NoError(t, err)
+	want, err := ioutil.ReadFile("./testdata/env.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{string(want), string(want)}, bodies)
+}
+
+func TestClient_ShouldNotRetryFlowUpload(t *testing.T) {
+	//given
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.json", "--url", ts.URL, "--retries", "3")
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClient_ShouldStopWaitingForRetryOnTimeout(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	//when
+	start := time.Now()
+	_, err := executeCommand("get", "flows", "--url", ts.URL, "--retries", "1", "--timeout", "100ms")
+
+	//then
+	require.Error(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestClient_ShouldUseRetriesAndTimeoutFromContext(t *testing.T) {
+	//given
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n  timeout: 30s\n  retries: 2\n", ts.URL))
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("get", "flows", "--config", config)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 30*time.Second, client.Timeout)
+}
+
+func TestClient_ShouldOverrideContextRetriesWithZero(t *testing.T) {
+	//given
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n  retries: 2\n", ts.URL))
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("get", "flows", "--config", config, "--retries", "0")
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClient_ShouldNotRetryCertificateErrors(t *testing.T) {
+	//given
+	var handshakes int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	ts.TLS = &tls.Config{GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		atomic.AddInt32(&handshakes, 1)
+		return nil, nil
+	}}
+	ts.StartTLS()
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL, "--retries", "3")
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signed by unknown authority")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&handshakes))
+}
+
+func TestClient_ShouldFailForInvalidTimeout(t *testing.T) {
+	_, err := executeCommand("get", "flows", "--url", "http://localhost:1", "--timeout", "soon")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid timeout: ")
+}
+
+func TestClient_ShouldReadRetryAfterInSecondsOrDate(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+
+	resp.Header.Set("Retry-After", "7")
+	d, ok := retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, d)
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	d, ok = retryAfter(resp)
+	assert.True(t, ok)
+	assert.True(t, d > 58*time.Second && d <= time.Minute, d.String())
+
+	resp.StatusCode = http.StatusBadGateway
+	_, ok = retryAfter(resp)
+	assert.False(t, ok)
+}
+
+func TestClient_ShouldBackoffExponentiallyWithJitter(t *testing.T) {
+	defer func(d time.Duration) { retryBackoff = d }(retryBackoff)
+	retryBackoff = time.Second
+
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		d := backoff(attempt)
+		assert.True(t, d >= max/2 && d <= max, d.String())
+	}
+	assert.True(t, backoff(20) <= maxRetryBackoff)
+}
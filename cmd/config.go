@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const defaultTimeout = time.Second * 5
+
+// flyteConfig is the content of the config file, by default ~/.flyte/config.yaml
+type flyteConfig struct {
+	CurrentContext string          `json:"current-context,omitempty"`
+	Contexts       []configContext `json:"contexts,omitempty"`
+}
+
+// configContext holds settings of one flyte API
+type configContext struct {
+	Name                  string      `json:"name"`
+	URL                   string      `json:"url,omitempty"`
+	Timeout               string      `json:"timeout,omitempty"`
+	Retries               int         `json:"retries,omitempty"`
+	Output                string      `json:"output,omitempty"`
+	CertificateAuthority  string      `json:"certificate-authority,omitempty"`
+	TLSServerName         string      `json:"tls-server-name,omitempty"`
+	InsecureSkipTLSVerify bool        `json:"insecure-skip-tls-verify,omitempty"`
+	Auth                  *authConfig `json:"auth,omitempty"`
+}
+
+// activeContext is the context used by the running command
+var activeContext configContext
+
+func (cfg *flyteConfig) context(name string) *configContext {
+	for i := range cfg.Contexts {
+		if cfg.Contexts[i].Name == name {
+			return &cfg.Contexts[i]
+		}
+	}
+	return nil
+}
+
+// apiURL is the flyte API URL from --url option, $FLYTE_API or the active context
+func apiURL() string {
+	if url := viper.GetString(flagURL); url != "" {
+		return url
+	}
+	return activeContext.URL
+}
+
+// configPath is the config file path from --config option, $FLYTE_CONFIG or the default one
+func configPath() string {
+	if path := viper.GetString(flagConfig); path != "" {
+		return path
+	}
+
+	home := os.Getenv("HOME")
+	if home == "" {
+		if u, err := user.Current(); err == nil {
+			home = u.HomeDir
+		}
+	}
+	return filepath.Join(home, ".flyte", "config.yaml")
+}
+
+// readConfig reads the config file, missing file is an empty config
+func readConfig(path string) (*flyteConfig, error) {
+	cfg := &flyteConfig{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config %s: %v", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("cannot read config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+func writeConfig(path string, cfg *flyteConfig) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("cannot write config %s: %v", path, err)
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("cannot write config %s: %v", path, err)
+	}
+	return nil
+}
+
+// loadContext sets up the active context from the config file for the command
+func loadContext(c *cobra.Command) error {
+	activeContext = configContext{}
+	client.Timeout = defaultTimeout
+
+	cfg, err := readConfig(configPath())
+	if err != nil {
+		return err
+	}
+
+	name := viper.GetString(flagContext)
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+	if name == "" {
+		return nil
+	}
+
+	ctx := cfg.context(name)
+	if ctx == nil {
+		return fmt.Errorf("context %q not found in config %s", name, configPath())
+	}
+	activeContext = *ctx
+
+	if ctx.Timeout != "" {
+		timeout, err := time.ParseDuration(ctx.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout in context %q: %v", name, err)
+		}
+		client.Timeout = timeout
+	}
+	return nil
+}
+
+// loadContextAndClient sets up the active context and the client for the command
+func loadContextAndClient(c *cobra.Command) error {
+	if err := loadContext(c); err != nil {
+		return err
+	}
+	return configureClient(c)
+}
+
+func newCmdConfig() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config SUBCOMMAND",
+		Short: "Modify config file",
+		Long:  longConfig,
+		// config commands must work even when the current context is broken
+		PersistentPreRunE: func(c *cobra.Command, args []string) error {
+			activeContext = configContext{}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(
+		newCmdConfigCurrentContext(),
+		newCmdConfigGetContexts(),
+		newCmdConfigSetContext(),
+		newCmdConfigUseContext(),
+	)
+	return cmd
+}
+
+const longConfig = `
+Modify config file with named contexts. Each context holds settings of one flyte API:
+URL, request timeout and retries, default output format, TLS settings and credentials.
+The current context is used unless --context option is passed, --url option and $FLYTE_API
+override context's URL, --timeout and --retries options override its timeout and retries.
+
+Credentials are a bearer token, basic auth username and password, or a client certificate
+and key. They are sent with every request to flyte API and can be overridden by --token,
+--username, --password, --client-certificate and --client-key options or $FLYTE_TOKEN,
+$FLYTE_USERNAME, $FLYTE_PASSWORD, $FLYTE_CLIENT_CERTIFICATE and $FLYTE_CLIENT_KEY.
+
+Server certificate is verified with system roots and the CA bundle from --certificate-authority,
+optionally against --tls-server-name instead of the URL host. --insecure-skip-tls-verify turns
+the verification off.
+
+The config file is read from --config option, $FLYTE_CONFIG or ~/.flyte/config.yaml
+
+Examples:
+  # Add dev context and make it the current one
+  flyte config set-context dev --url http://flyte.dev:8080 --timeout 10s
+  flyte config use-context dev
+
+  # Use a bearer token for prod context
+  flyte config set-context prod --url https://flyte.prod --token $PROD_TOKEN --certificate-authority ./ca.crt
+
+  # List all contexts
+  flyte config get-contexts
+
+  # Get flows from prod context once
+  flyte get flows --context prod
+`
+
+func newCmdConfigCurrentContext() *cobra.Command {
+	return &cobra.Command{
+		Use:   "current-context",
+		Short: "Display the current context",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			cfg, err := readConfig(configPath())
+			if err != nil {
+				return err
+			}
+			if cfg.CurrentContext == "" {
+				return errors.New("current context is not set")
+			}
+			_, err = fmt.Fprintln(c.OutOrStdout(), cfg.CurrentContext)
+			return err
+		},
+	}
+}
+
+func newCmdConfigGetContexts() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-contexts",
+		Short: "List all contexts",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			cfg, err := readConfig(configPath())
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(c.OutOrStdout(), 0, 8, 2, ' ', 0)
+			fmt.Fprintln(w, "CURRENT\tNAME\tURL\tTIMEOUT\tOUTPUT")
+			for _, ctx := range cfg.Contexts {
+				current := ""
+				if ctx.Name == cfg.CurrentContext {
+					current = "*"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, ctx.Name, ctx.URL, ctx.Timeout, ctx.Output)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+var argsSetContext = struct {
+	configContext
+	auth authConfig
+}{}
+
+func newCmdConfigSetContext() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-context NAME",
+		Short: "Create a context or update its settings",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runConfigSetContext,
+	}
+
+	cmd.Flags().StringVar(&argsSetContext.URL, flagURL, "", "flyte API URL")
+	cmd.Flags().StringVar(&argsSetContext.Timeout, flagTimeout, "", "timeout of a request to flyte API, e.g. 10s")
+	cmd.Flags().IntVar(&argsSetContext.Retries, flagRetries, 0, "number of retries of idempotent requests to flyte API")
+	cmd.Flags().StringVar(&argsSetContext.Output, flagOutput, "", "default output format. One of: json|yaml")
+	cmd.Flags().StringVar(&argsSetContext.CertificateAuthority, flagCertificateAuthority, "", "CA bundle file to verify flyte API certificate")
+	cmd.Flags().StringVar(&argsSetContext.TLSServerName, flagTLSServerName, "", "server name to verify flyte API certificate against")
+	cmd.Flags().BoolVar(&argsSetContext.InsecureSkipTLSVerify, flagInsecureSkipTLSVerify, false, "do not verify flyte API certificate")
+	cmd.Flags().StringVar(&argsSetContext.auth.Token, flagToken, "", "bearer token for authentication")
+	cmd.Flags().StringVar(&argsSetContext.auth.Username, flagUsername, "", "username for basic authentication")
+	cmd.Flags().StringVar(&argsSetContext.auth.Password, flagPassword, "", "password for basic authentication")
+	cmd.Flags().StringVar(&argsSetContext.auth.ClientCertificate, flagClientCertificate, "", "client certificate file for TLS authentication")
+	cmd.Flags().StringVar(&argsSetContext.auth.ClientKey, flagClientKey, "", "client key file for TLS authentication")
+	return cmd
+}
+
+func runConfigSetContext(c *cobra.Command, args []string) error {
+	if argsSetContext.Timeout != "" {
+		if _, err := time.ParseDuration(argsSetContext.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout: %v", err)
+		}
+	}
+	if argsSetContext.Retries < 0 {
+		return fmt.Errorf("invalid retries: %d must not be negative", argsSetContext.Retries)
+	}
+	switch argsSetContext.Output {
+	case "", outputJSON, outputYAML:
+	default:
+		return fmt.Errorf("invalid output: %q must be one of: json|yaml", argsSetContext.Output)
+	}
+
+	path := configPath()
+	cfg, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	ctx := cfg.context(name)
+	if ctx == nil {
+		cfg.Contexts = append(cfg.Contexts, configContext{Name: name})
+		ctx = &cfg.Contexts[len(cfg.Contexts)-1]
+	}
+
+	flags := c.Flags()
+	if flags.Changed(flagURL) {
+		ctx.URL = argsSetContext.URL
+	}
+	if flags.Changed(flagTimeout) {
+		ctx.Timeout = argsSetContext.Timeout
+	}
+	if flags.Changed(flagRetries) {
+		ctx.Retries = argsSetContext.Retries
+	}
+	if flags.Changed(flagOutput) {
+		ctx.Output = argsSetContext.Output
+	}
+	if flags.Changed(flagCertificateAuthority) {
+		ctx.CertificateAuthority = argsSetContext.CertificateAuthority
+	}
+	if flags.Changed(flagTLSServerName) {
+		ctx.TLSServerName = argsSetContext.TLSServerName
+	}
+	if flags.Changed(flagInsecureSkipTLSVerify) {
+		ctx.InsecureSkipTLSVerify = argsSetContext.InsecureSkipTLSVerify
+	}
+	setContextAuth(c, ctx)
+
+	if err := writeConfig(path, cfg); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.OutOrStdout(), "context %q set\n", name)
+	return err
+}
+
+// setContextAuth updates credentials of the context from the changed options, empty credentials are removed
+func setContextAuth(c *cobra.Command, ctx *configContext) {
+	auth := authConfig{}
+	if ctx.Auth != nil {
+		auth = *ctx.Auth
+	}
+
+	flags := c.Flags()
+	if flags.Changed(flagToken) {
+		auth.Token = argsSetContext.auth.Token
+	}
+	if flags.Changed(flagUsername) {
+		auth.Username = argsSetContext.auth.Username
+	}
+	if flags.Changed(flagPassword) {
+		auth.Password = argsSetContext.auth.Password
+	}
+	if flags.Changed(flagClientCertificate) {
+		auth.ClientCertificate = argsSetContext.auth.ClientCertificate
+	}
+	if flags.Changed(flagClientKey) {
+		auth.ClientKey = argsSetContext.auth.ClientKey
+	}
+
+	if auth == (authConfig{}) {
+		ctx.Auth = nil
+		return
+	}
+	ctx.Auth = &auth
+}
+
+func newCmdConfigUseContext() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use-context NAME",
+		Short: "Set the current context",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			path := configPath()
+			cfg, err := readConfig(path)
+			if err != nil {
+				return err
+			}
+
+			name := args[0]
+			if cfg.context(name) == nil {
+				return fmt.Errorf("context %q not found in config %s", name, path)
+			}
+
+			cfg.CurrentContext = name
+			if err := writeConfig(path, cfg); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(c.OutOrStdout(), "switched to context %q\n", name)
+			return err
+		},
+	}
+}
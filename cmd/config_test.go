@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// tests must not depend on the config file of the user running them
+	os.Setenv("FLYTE_CONFIG", filepath.Join(os.TempDir(), "flyte-cli-test-missing-config.yaml"))
+}
+
+func TestConfig_ShouldSetAndUseContexts(t *testing.T) {
+	//given
+	config, cleanup := tempConfig(t, "")
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("config", "set-context", "dev", "--url", "http://dev:8080", "--timeout", "10s", "--config", config)
+	require.NoError(t, err)
+	_, err = executeCommand("config", "set-context", "prod", "--url", "http://prod:8080", "--output", "yaml", "--config", config)
+	require.NoError(t, err)
+	output, err := executeCommand("config", "use-context", "prod", "--config", config)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, "switched to context \"prod\"\n", output)
+
+	output, err = executeCommand("config", "get-contexts", "--config", config)
+	require.NoError(t, err)
+	assert.Equal(t, "CURRENT  NAME  URL               TIMEOUT  OUTPUT\n"+
+		"         dev   http://dev:8080   10s      \n"+
+		"*        prod  http://prod:8080           yaml\n", output)
+
+	output, err = executeCommand("config", "current-context", "--config", config)
+	require.NoError(t, err)
+	assert.Equal(t, "prod\n", output)
+}
+
+func TestConfig_ShouldUpdateOnlyGivenSettingsOfExistingContext(t *testing.T) {
+	//given
+	config, cleanup := tempConfig(t, "contexts:\n- name: dev\n  url: http://dev:8080\n  output: yaml\n")
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("config", "set-context", "dev", "--timeout", "1m", "--config", config)
+	require.NoError(t, err)
+
+	//then
+	data, err := ioutil.ReadFile(config)
+	require.NoError(t, err)
+	assert.Equal(t, "contexts:\n- name: dev\n  output: yaml\n  timeout: 1m\n  url: http://dev:8080\n", string(data))
+}
+
+func TestConfig_ShouldFailToSetInvalidOutput(t *testing.T) {
+	config, cleanup := tempConfig(t, "")
+	defer cleanup()
+
+	_, err := executeCommand("config", "set-context", "dev", "--output", "table", "--config", config)
+
+	require.Error(t, err)
+	assert.Equal(t, "invalid output: \"table\" must be one of: json|yaml", err.Error())
+	_, statErr := os.Stat(config)
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestConfig_ShouldFailToUseUnknownContext(t *testing.T) {
+	config, cleanup := tempConfig(t, "")
+	defer cleanup()
+
+	_, err := executeCommand("config", "use-context", "dev", "--config", config)
+
+	require.Error(t, err)
+	assert.Equal(t, fmt.Sprintf("context \"dev\" not found in config %s", config), err.Error())
+}
+
+func TestConfig_ShouldUseCurrentContextURLAndOutput(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"my-flow","steps":[]}`)
+	}))
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n  output: yaml\n", ts.URL))
+	defer cleanup()
+
+	//when
+	output, err := executeCommand("get", "flow", "my-flow", "--config", config)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, "name: my-flow\nsteps: []\n\n", output)
+}
+
+func TestConfig_ShouldPreferUrlOptionAndContextOption(t *testing.T) {
+	//given
+	config, cleanup := tempConfig(t, "current-context: dev\ncontexts:\n- name: dev\n  url: http://dev:8080\n- name: prod\n  url: http://prod:8080\n")
+	defer cleanup()
+
+	//when
+	withContext, err := executeCommand("version", "--context", "prod", "--config", config)
+	require.NoError(t, err)
+	withURL, err := executeCommand("version", "--url", "http://local:8080", "--config", config)
+	require.NoError(t, err)
+
+	//then
+	assert.Contains(t, withContext, "API URL:\thttp://prod:8080\nContext:\tprod\n")
+	assert.Contains(t, withURL, "API URL:\thttp://local:8080\nContext:\tdev\n")
+}
+
+func TestConfig_ShouldFailForUnknownContextOption(t *testing.T) {
+	config, cleanup := tempConfig(t, "")
+	defer cleanup()
+
+	_, err := executeCommand("version", "--context", "qa", "--config", config)
+
+	require.Error(t, err)
+	assert.Equal(t, fmt.Sprintf("context \"qa\" not found in config %s", config), err.Error())
+}
+
+// tempConfig creates config file with the content, empty content means no file
+func tempConfig(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+
+	config := filepath.Join(dir, ".flyte", "config.yaml")
+	if content != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(config), 0700))
+		require.NoError(t, ioutil.WriteFile(config, []byte(content), 0600))
+	}
+	return config, func() {
+		os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// encryptedExt marks files encrypted by 'flyte ds encrypt'
+	encryptedExt = ".enc"
+	// encryptedPrefix starts the content of encrypted files, followed by base64 encoded nonce and ciphertext
+	encryptedPrefix = "flyte:v1:"
+)
+
+// plainFilename is the filename without the encrypted extension, names and content types are derived from it
+func plainFilename(filename string) string {
+	return strings.TrimSuffix(filename, encryptedExt)
+}
+
+// readDsValue reads the datastore item's value from the file, encrypted files are decrypted in memory
+func readDsValue(filename string) ([]byte, error) {
+	data, err := readFile(filename)
+	if err != nil || !strings.HasSuffix(filename, encryptedExt) {
+		return data, err
+	}
+
+	key, err := readKey()
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt %s: %v", filename, err)
+	}
+	value, err := decrypt(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt %s: %v", filename, err)
+	}
+	return value, nil
+}
+
+// readKey reads the base64 encoded AES-256 key from the file set by --key-file option
+func readKey() ([]byte, error) {
+	filename := viper.GetString(flagKeyFile)
+	if filename == "" {
+		return nil, fmt.Errorf("key file is not set, use --%s option or $FLYTE_KEY_FILE", flagKeyFile)
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid key file %s, it must contain base64 encoded 32 bytes key", filename)
+	}
+	return key, nil
+}
+
+// encrypt encrypts the value with AES-256-GCM, the result is text so it can be kept in git
+func encrypt(key, value []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, value, nil)
+	return []byte(encryptedPrefix + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+func decrypt(key, data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte(encryptedPrefix)) {
+		return nil, errors.New("file is not encrypted by 'flyte ds encrypt'")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(data[len(encryptedPrefix):]))
+	if err != nil {
+		return nil, fmt.Errorf("file is corrupted: %v", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("file is corrupted")
+	}
+	value, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("wrong key or the file is corrupted")
+	}
+	return value, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var argsDelete = struct {
+	yes            bool
+	ignoreNotFound bool
+}{}
+
+func newCmdDelete() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete TYPE NAME...",
+		Short:   "Delete resources by names",
+		Long:    longDelete,
+		Example: exampleDelete,
+	}
+
+	cmd.PersistentFlags().BoolVarP(&argsDelete.yes, flagYes, "y", false, "delete without asking for confirmation")
+	cmd.PersistentFlags().BoolVar(&argsDelete.ignoreNotFound, "ignore-not-found", false, "treat resources which do not exist as deleted")
+
+	cmd.SetUsageTemplate(usageTmplResource)
+	cmd.AddCommand(newCmdDeleteFlow(), newCmdDeleteDs())
+	return cmd
+}
+
+const longDelete = `
+
+Delete one or more resources by names from a flyte API. Valid resource types include:
+
+  * datastore (aka 'ds')
+  * flow`
+
+const exampleDelete = `  # Delete my-flow from flyte API specified by $FLYTE_API
+  flyte delete flow my-flow
+
+  # Delete env and my-script datastore items without asking for confirmation
+  flyte delete ds env my-script --yes`
+
+// runDelete deletes every named resource of the given kind and reports the result of each deletion.
+// Resources which do not exist are reported separately from the other failures.
+// On dry run the delete requests are printed without asking for confirmation.
+func runDelete(c *cobra.Command, kind string, names []string, resourceURL func(apiURL, name string) string) error {
+	if isDryRun() {
+		for _, name := range names {
+			req, err := http.NewRequest(http.MethodDelete, resourceURL(apiURL(), name), nil)
+			if err != nil {
+				return err
+			}
+			if err := printRequest(c, req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !argsDelete.yes && !confirm(c, fmt.Sprintf("Delete %s %s?", kind, quoteAll(names))) {
+		_, err := fmt.Fprintln(c.OutOrStdout(), "Aborted")
+		return err
+	}
+
+	out := c.OutOrStdout()
+	failed := 0
+	for _, name := range names {
+		err := deleteResource(resourceURL(apiURL(), name))
+		switch {
+		case err == nil:
+			fmt.Fprintf(out, "%s %q deleted\n", kind, name)
+		case isNotFound(err) && argsDelete.ignoreNotFound:
+			fmt.Fprintf(out, "%s %q not found, skipped\n", kind, name)
+		case isNotFound(err):
+			fmt.Fprintf(out, "%s %q not found\n", kind, name)
+			failed++
+		default:
+			fmt.Fprintf(out, "cannot delete %s %q\n%s\n", kind, name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("cannot delete %d of %d %s(s)", failed, len(names), kind)
+	}
+	return nil
+}
+
+// confirm asks user the question and waits for the answer
+func confirm(c *cobra.Command, question string) bool {
+	fmt.Fprintf(c.OutOrStdout(), "%s [y/N]: ", question)
+
+	answer, _ := bufio.NewReader(stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(quoted, ", ")
+}
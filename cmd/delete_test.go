@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteFlow_ShouldDeleteFlowsWithoutConfirmation(t *testing.T) {
+	//given
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, r.URL.String())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("delete", "flow", "my-flow", "other-flow", "--yes", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, []string{flytepath.FlowsPath + "/my-flow", flytepath.FlowsPath + "/other-flow"}, deleted)
+	assert.Equal(t, "flow \"my-flow\" deleted\nflow \"other-flow\" deleted\n", output)
+}
+
+func TestDeleteDs_ShouldDeleteItemWhenConfirmed(t *testing.T) {
+	//given
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.Method+" "+r.URL.String())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	defer replaceStdin("y\n")()
+
+	//when
+	output, err := executeCommand("delete", "ds", "env", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, []string{http.MethodDelete + " " + flytepath.DatastorePath + "/env"}, deleted)
+	assert.Equal(t, "Delete datastore item \"env\"? [y/N]: datastore item \"env\" deleted\n", output)
+}
+
+func TestDelete_ShouldNotDeleteWhenNotConfirmed(t *testing.T) {
+	//given
+	called := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer ts.Close()
+	defer replaceStdin("n\n")()
+
+	//when
+	output, err := executeCommand("delete", "flow", "my-flow", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.False(t, called)
+	assert.Contains(t, output, "Aborted")
+}
+
+func TestDelete_ShouldReportNotFoundSeparatelyFromFailures(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case flytepath.FlowsPath + "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case flytepath.FlowsPath + "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("delete", "flow", "missing", "broken", "my-flow", "-y", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot delete 2 of 3 flow(s)", err.Error())
+	assert.Contains(t, output, "flow \"missing\" not found\n")
+	assert.Contains(t, output, "cannot delete flow \"broken\"\nHTTP/1.1 500 Internal Server Error")
+	assert.Contains(t, output, "flow \"my-flow\" deleted\n")
+}
+
+func TestDelete_ShouldIgnoreNotFound(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("delete", "ds", "env", "-y", "--ignore-not-found", "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore item \"env\" not found, skipped\n", output)
+}
+
+// replaceStdin replaces stdin with the input and returns function restoring it
+func replaceStdin(input string) func() {
+	orig := stdin
+	stdin = strings.NewReader(input)
+	return func() {
+		stdin = orig
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCmdDeleteDs() *cobra.Command {
+	return &cobra.Command{
+		Use:     "datastore NAME...",
+		Aliases: []string{"ds"},
+		Short:   "Delete datastore items by names",
+		Long:    longDeleteDs,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runDelete(c, "datastore item", args, dsItemURL)
+		},
+	}
+}
+
+const longDeleteDs = `
+Delete one or more datastore items by names from a flyte API.
+Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
+
+Examples:
+  # Delete env datastore item from flyte API specified by $FLYTE_API
+  flyte delete ds env
+
+  # Delete env and my-script datastore items from flyte API at http://127.0.0.1:8080 without confirmation
+  flyte delete ds env my-script --yes --url http://127.0.0.1:8080
+`
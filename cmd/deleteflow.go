@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCmdDeleteFlow() *cobra.Command {
+	return &cobra.Command{
+		Use:   "flow NAME...",
+		Short: "Delete flows by names",
+		Long:  longDeleteFlow,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runDelete(c, "flow", args, flowURL)
+		},
+	}
+}
+
+const longDeleteFlow = `
+Delete one or more flows by names from a flyte API.
+Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
+
+Examples:
+  # Delete my-flow from flyte API specified by $FLYTE_API
+  flyte delete flow my-flow
+
+  # Delete my-flow and other-flow from flyte API at http://127.0.0.1:8080 without confirmation
+  flyte delete flow my-flow other-flow --yes --url http://127.0.0.1:8080
+`
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCmdDescribe() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "describe TYPE NAME",
+		Short:   "Show details of a resource",
+		Long:    longDescribe,
+		Example: exampleDescribe,
+	}
+
+	cmd.SetUsageTemplate(usageTmplResource)
+	cmd.AddCommand(newCmdDescribePack())
+	return cmd
+}
+
+const longDescribe = `
+
+Show details of a resource from a flyte API. Valid resource types include:
+
+  * pack`
+
+const exampleDescribe = `  # Describe events and commands of Slack pack
+  flyte describe pack Slack`
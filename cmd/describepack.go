@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var argsDescribePack = struct {
+	format string
+}{}
+
+func newCmdDescribePack() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pack NAME",
+		Short: "Show events and commands declared by a pack",
+		Long:  longDescribePack,
+		Args:  cobra.ExactArgs(1),
+		RunE:  runDescribePack,
+	}
+
+	cmd.Flags().StringVar(&argsDescribePack.format, flagFormat, "", "Output format. One of: json|yaml (default human readable text)")
+	cmd.Flags().MarkDeprecated(flagFormat, "use -o/--output instead")
+	return cmd
+}
+
+const longDescribePack = `
+Show events and commands declared by a pack registered in a flyte API.
+Pack is looked up by its id first and then by its name.
+Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
+
+Examples:
+  # Describe Slack pack
+  flyte describe pack Slack
+
+  # Describe Slack pack as yaml
+  flyte describe pack Slack -o yaml
+`
+
+func runDescribePack(c *cobra.Command, args []string) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	p, err := findPack(apiURL(), args[0])
+	if err != nil {
+		return err
+	}
+
+	if format != "" {
+		return printMarshalled(c, p, format)
+	}
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 8, 1, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", p.ID)
+	fmt.Fprintf(w, "Name:\t%s\n", p.Name)
+	fmt.Fprintf(w, "Labels:\t%s\n", formatLabels(p.Labels))
+	fmt.Fprintf(w, "Status:\t%s\n", p.Status)
+	fmt.Fprintln(w, "Events:")
+	for _, e := range p.Events {
+		fmt.Fprintf(w, "  %s\n", e.Name)
+	}
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range p.Commands {
+		if len(cmd.Events) == 0 {
+			fmt.Fprintf(w, "  %s\n", cmd.Name)
+			continue
+		}
+		fmt.Fprintf(w, "  %s -> %s\n", cmd.Name, strings.Join(cmd.Events, ", "))
+	}
+	return w.Flush()
+}
+
+// findPack gets the pack by id, if there is no such pack it looks for a single pack with the name
+func findPack(apiURL, name string) (*pack, error) {
+	var p pack
+	err := getJSON(packURL(apiURL, name), &p)
+	if err == nil {
+		return &p, nil
+	}
+	if !isNotFound(err) {
+		return nil, fmt.Errorf("cannot get pack\n%s", err)
+	}
+
+	packs, err := listPacks(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, p := range packs {
+		if p.Name == name {
+			ids = append(ids, p.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return nil, fmt.Errorf("cannot get pack: %s not found", name)
+	case 1:
+		if err := getJSON(packURL(apiURL, ids[0]), &p); err != nil {
+			return nil, fmt.Errorf("cannot get pack\n%s", err)
+		}
+		return &p, nil
+	default:
+		return nil, fmt.Errorf("cannot get pack: there are %d packs named %s, use one of ids: %s", len(ids), name, strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribePack_ShouldDescribePackById(t *testing.T) {
+	//given
+	rec := requestRec{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.request = *r
+		fmt.Fprint(w, slackPackResponse)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("describe", "pack", "Slack", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, flytepath.PacksPath+"/Slack", rec.request.URL.String())
+	assert.Equal(t, `ID:     Slack
+Name:   Slack
+Labels: env=prod
+Status: live
+Events:
+  ReceivedMessage
+Commands:
+  SendMessage -> MessageSent, SendMessageFailed
+  Ping
+`, output)
+}
+
+func TestDescribePack_ShouldFallbackToPackName(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case flytepath.PacksPath:
+			fmt.Fprint(w, `{"packs":[{"id":"Slack.env:prod","name":"Slack"},{"id":"Shell","name":"Shell"}]}`)
+		case flytepath.PacksPath + "/Slack.env:prod":
+			fmt.Fprint(w, slackPackResponse)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("describe", "pack", "Slack", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Contains(t, output, "SendMessage -> MessageSent, SendMessageFailed")
+}
+
+func TestDescribePack_ShouldFailWhenPackNameIsAmbiguous(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != flytepath.PacksPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"packs":[{"id":"Slack.env:prod","name":"Slack"},{"id":"Slack.env:dev","name":"Slack"}]}`)
+	}))
+	defer ts.Close()
+
+	_, err := executeCommand("describe", "pack", "Slack", "--url", ts.URL)
+	require.Error(t, err)
+
+	assert.Equal(t, "cannot get pack: there are 2 packs named Slack, use one of ids: Slack.env:prod, Slack.env:dev", err.Error())
+}
+
+func TestDescribePack_ShouldFailWhenPackNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != flytepath.PacksPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"packs":[]}`)
+	}))
+	defer ts.Close()
+
+	_, err := executeCommand("describe", "pack", "Slack", "--url", ts.URL)
+	require.Error(t, err)
+
+	assert.Equal(t, "cannot get pack: Slack not found", err.Error())
+}
+
+const slackPackResponse = `{
+	"id":"Slack",
+	"name":"Slack",
+	"labels":{"env":"prod"},
+	"status":"live",
+	"events":[{"name":"ReceivedMessage","links":[]}],
+	"commands":[{"name":"SendMessage","events":["MessageSent","SendMessageFailed"]},{"name":"Ping"}]
+}`
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+var argsDiff = struct {
+	dir string
+}{}
+
+func newCmdDiff() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff -f DIR",
+		Short: "Show differences between a directory and flows and datastore items in a flyte API",
+		Long:  longDiff,
+		Args:  cobra.NoArgs,
+		RunE:  runDiff,
+	}
+
+	cmd.Flags().StringVarP(&argsDiff.dir, flagFilename, "f", "", "directory with flow and datastore item files")
+	cmd.MarkFlagRequired(flagFilename)
+	return cmd
+}
+
+const longDiff = `
+Show what 'flyte apply' would change. Flows and datastore items are read from the directory
+the same way as by apply, and compared with the ones in the flyte API. A unified diff is
+printed for every resource which differs or does not exist in the flyte API.
+
+Flows and JSON or YAML datastore values are normalized before the comparison, so neither
+the format nor the order of keys makes a difference. Content type and description of
+datastore items are compared too, the description only when it is set by the manifest.
+Values of encrypted .enc files are not printed, they are compared by their sha256 hash.
+
+The command fails when there are any differences, so it can be used to detect drift in CI.
+
+Examples:
+  # Preview changes of prod flyte API
+  flyte diff -f ./flyte --context prod
+`
+
+func runDiff(c *cobra.Command, args []string) error {
+	resources, err := readResources(argsDiff.dir)
+	if err != nil {
+		return err
+	}
+
+	items, err := listDsItems(apiURL())
+	if err != nil {
+		return err
+	}
+
+	differ := 0
+	for _, r := range resources {
+		remote, err := fetchRemote(apiURL(), r, items)
+		if err != nil {
+			return fmt.Errorf("cannot get %s/%s\n%s", r.kind, r.name, err)
+		}
+
+		local := renderLocal(r)
+		if remote.rendered == local {
+			continue
+		}
+		differ++
+
+		var remoteLines []string
+		if remote.exists {
+			remoteLines = splitLines(remote.rendered)
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        remoteLines,
+			B:        splitLines(local),
+			FromFile: fmt.Sprintf("%s/%s (flyte API)", r.kind, r.name),
+			ToFile:   fmt.Sprintf("%s/%s (%s)", r.kind, r.name, r.filename),
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(c.OutOrStdout(), diff)
+	}
+
+	if differ > 0 {
+		return fmt.Errorf("%d of %d resource(s) differ from flyte API", differ, len(resources))
+	}
+	return nil
+}
+
+// splitLines splits rendered resource, which always ends with a new line, into lines for the diff
+func splitLines(s string) []string {
+	return difflib.SplitLines(strings.TrimSuffix(s, "\n"))
+}
+
+// remoteResource is the resource in flyte API rendered for the comparison with the local one
+type remoteResource struct {
+	rendered string
+	exists   bool
+	// flow is the flow definition, it is kept so the flow can be restored when it cannot be replaced
+	flow map[string]interface{}
+}
+
+// fetchRemote gets the resource from flyte API, datastore items are looked up in the items
+func fetchRemote(apiURL string, r resource, items map[string]dsSummary) (remoteResource, error) {
+	if r.kind == kindFlow {
+		flow, err := fetchFlow(flowURL(apiURL, r.name))
+		if err != nil || flow == nil {
+			return remoteResource{}, err
+		}
+		return remoteResource{rendered: renderValue(flow), exists: true, flow: flow}, nil
+	}
+
+	existing, ok := items[r.name]
+	if !ok {
+		return remoteResource{}, nil
+	}
+	value, contentType, err := getDatastoreValue(dsItemURL(apiURL, r.name))
+	if err != nil {
+		return remoteResource{}, err
+	}
+
+	description := ""
+	if r.description != "" {
+		description = existing.Description
+	}
+	return remoteResource{rendered: renderDs(value, contentType, description, r.encrypted()), exists: true}, nil
+}
+
+// fetchFlow gets the flow definition without API links, it is nil when the flow does not exist
+func fetchFlow(url string) (map[string]interface{}, error) {
+	flow := map[string]interface{}{}
+	if err := getJSON(url, &flow); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// links are added by the API and are not part of the flow definition
+	delete(flow, "links")
+	return flow, nil
+}
+
+// renderLocal renders the resource from the file for the comparison with the one in flyte API
+func renderLocal(r resource) string {
+	if r.kind == kindFlow {
+		var flow interface{}
+		if err := yaml.Unmarshal(r.data, &flow); err != nil {
+			return string(r.data)
+		}
+		return renderValue(flow)
+	}
+	return renderDs(r.data, r.contentType, r.description, r.encrypted())
+}
+
+// encrypted is true for datastore items from files encrypted by 'flyte ds encrypt'
+func (r resource) encrypted() bool {
+	return strings.HasSuffix(r.filename, encryptedExt)
+}
+
+// renderValue renders the value as yaml with sorted keys
+func renderValue(v interface{}) string {
+	out, err := yaml.Marshal(normalize(v))
+	if err != nil {
+		return fmt.Sprintf("%v\n", v)
+	}
+	return string(out)
+}
+
+// renderDs renders datastore item's content type, description and value, JSON and YAML values are normalized.
+// Encrypted values are replaced by the hash of the normalized value, so secrets are not printed by the diff.
+func renderDs(value []byte, contentType, description string, encrypted bool) string {
+	mediaType := contentType
+	if t, _, err := mime.ParseMediaType(contentType); err == nil {
+		mediaType = t
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "contentType: %s\n", mediaType)
+	if description != "" {
+		fmt.Fprintf(&b, "description: %s\n", description)
+	}
+	b.WriteString("value:\n")
+	rendered := renderDsValue(value, mediaType)
+	if encrypted {
+		rendered = fmt.Sprintf("encrypted value, sha256 %x\n", sha256.Sum256([]byte(rendered)))
+	}
+	b.WriteString(rendered)
+	return b.String()
+}
+
+func renderDsValue(value []byte, mediaType string) string {
+	if !utf8.Valid(value) {
+		return fmt.Sprintf("binary value of %d bytes, sha256 %x\n", len(value), sha256.Sum256(value))
+	}
+
+	var v interface{}
+	switch {
+	case mediaType == httputil.MediaTypeJson && json.Unmarshal(value, &v) == nil:
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err == nil {
+			return string(out) + "\n"
+		}
+	case mediaType == httputil.MediaTypeYaml && yaml.Unmarshal(value, &v) == nil:
+		return renderValue(v)
+	}
+
+	s := string(value)
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n\\ No newline at end of value\n"
+	}
+	return s
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_ShouldPrintNothingWhenSameAsAPI(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	_, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//when
+	output, err := executeCommand("diff", "-f", "./testdata/apply", "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "", output)
+}
+
+func TestDiff_ShouldDetectContentTypeOfFilesWithoutExtension(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "env"), []byte(`{"channel": "123"}`), 0644))
+
+	_, err = executeCommand("upload", "ds", "-f", dir, "--url", ts.URL)
+	require.NoError(t, err)
+
+	//when
+	output, err := executeCommand("diff", "-f", dir, "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "", output)
+}
+
+func TestDiff_ShouldIgnoreFormatAndKeyOrder(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["status"] = map[string]interface{}{
+		"steps": []interface{}{map[string]interface{}{
+			"command": map[string]interface{}{
+				"input":    map[string]interface{}{"message": "I am up and running"},
+				"name":     "SendMessage",
+				"packName": "Slack",
+			},
+			"event": map[string]interface{}{"name": "ReceivedMessage", "packName": "Slack"},
+			"id":    "status",
+		}},
+		"description": "Replies with the bot status",
+		"name":        "status",
+	}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("diff", "-f", "./testdata/apply/flows", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.NotContains(t, output, "flow/status")
+	assert.Contains(t, output, "+++ flow/deploy (testdata/apply/flows/deploy.json)")
+	assert.Equal(t, "1 of 2 resource(s) differ from flyte API", err.Error())
+}
+
+func TestDiff_ShouldPrintUnifiedDiffOfChangedResources(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["status"] = map[string]interface{}{
+		"name":        "status",
+		"description": "Replies with the bot status",
+		"steps": []interface{}{map[string]interface{}{
+			"id":    "status",
+			"event": map[string]interface{}{"packName": "Slack", "name": "ReceivedMessage"},
+			"command": map[string]interface{}{
+				"packName": "Slack",
+				"name":     "SendMessage",
+				"input":    map[string]interface{}{"message": "I am down"},
+			},
+		}},
+	}
+	api.ds["env"] = fakeDsItem{value: []byte(`{"channel":"456"}`), contentType: "application/json", description: "Slack environment"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("diff", "-f", "./testdata/apply", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "4 of 4 resource(s) differ from flyte API", err.Error())
+
+	assert.Contains(t, output, `--- datastore/env (flyte API)
++++ datastore/env (testdata/apply/ds/slack-env.json)
+@@ -2,5 +2,5 @@
+ description: Slack environment
+ value:
+ {
+-  "channel": "456"
++  "channel": "123"
+ }
+`)
+	assert.Contains(t, output, `--- flow/status (flyte API)
++++ flow/status (testdata/apply/flows/status.yaml)
+@@ -3,7 +3,7 @@
+ steps:
+ - command:
+     input:
+-      message: I am down
++      message: I am up and running
+     name: SendMessage
+     packName: Slack
+   event:
+`)
+	assert.Contains(t, output, `--- datastore/hello (flyte API)
++++ datastore/hello (testdata/apply/ds/hello.sh)
+@@ -0,0 +1,4 @@
++contentType: application/x-sh
++value:
++#!/bin/sh
++echo hello
+`)
+}
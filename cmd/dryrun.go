@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// isDryRun is true when the mutating requests must be printed instead of sent
+func isDryRun() bool {
+	return viper.GetBool(flagDryRun)
+}
+
+// printRequest prints method, URL, headers and a summary of the body of the request which would be sent.
+// Credentials are not printed.
+func printRequest(c *cobra.Command, req *http.Request) error {
+	out := c.OutOrStdout()
+	fmt.Fprintf(out, "%s %s\n", req.Method, req.URL)
+
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(out, "%s: %s\n", k, strings.Join(req.Header[k], ", "))
+	}
+	if scheme := resolveAuth().scheme(); scheme != "" {
+		fmt.Fprintf(out, "Authorization: %s <redacted>\n", scheme)
+	}
+
+	summary, err := bodySummary(req)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(out, summary)
+	return err
+}
+
+// bodySummary describes the size of the body, multipart body is described part by part
+func bodySummary(req *http.Request) (string, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return "Body: none\n", nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Body: %d bytes\n", len(data))
+
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return b.String(), nil
+	}
+
+	r := multipart.NewReader(bytes.NewReader(data), params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		value, err := ioutil.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+		if part.FileName() != "" {
+			fmt.Fprintf(&b, "  %s: file %s (%s, %d bytes)\n", part.FormName(), part.FileName(), part.Header.Get("Content-Type"), len(value))
+		} else {
+			fmt.Fprintf(&b, "  %s: %q\n", part.FormName(), value)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecordingServer counts requests which must not be sent on dry run
+func newRecordingServer(requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.WriteHeader(http.StatusCreated)
+	}))
+}
+
+func TestDryRun_ShouldPrintDatastoreRequestWithoutSendingIt(t *testing.T) {
+	//given
+	requests := 0
+	ts := newRecordingServer(&requests)
+	defer ts.Close()
+
+	value, err := ioutil.ReadFile("./testdata/env.json")
+	require.NoError(t, err)
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", "./testdata/env.json", "-d", "My env", "--url", ts.URL, "--dry-run")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, 0, requests)
+	assert.Contains(t, output, "PUT "+ts.URL+flytepath.DatastorePath+"/env\nContent-Type: multipart/form-data; boundary=")
+	assert.Contains(t, output, fmt.Sprintf("  value: file env.json (application/json, %d bytes)\n  description: \"My env\"\n", len(value)))
+}
+
+func TestDryRun_ShouldPrintFlowRequestWithoutCredentials(t *testing.T) {
+	//given
+	requests := 0
+	ts := newRecordingServer(&requests)
+	defer ts.Close()
+
+	value, err := ioutil.ReadFile("./testdata/my-flow.yaml")
+	require.NoError(t, err)
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.yaml", "--url", ts.URL, "--token", "secret", "--dry-run")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, 0, requests)
+	assert.Equal(t, fmt.Sprintf("POST %s%s\nContent-Type: %s\nAuthorization: Bearer <redacted>\nBody: %d bytes\n",
+		ts.URL, flytepath.FlowsPath, httputil.MediaTypeYaml, len(value)), output)
+}
+
+func TestDryRun_ShouldFailForFlowWhichCannotBeParsed(t *testing.T) {
+	_, err := executeCommand("upload", "flow", "-f", "./testdata/invalid-flow.json", "--url", "http://localhost:1", "--dry-run")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot upload flow: ./testdata/invalid-flow.json:4: cannot parse flow: invalid character '}'")
+}
+
+func TestDryRun_ShouldPrintDeleteRequestsWithoutConfirmation(t *testing.T) {
+	//given
+	requests := 0
+	ts := newRecordingServer(&requests)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("delete", "flow", "my-flow", "other-flow", "--url", ts.URL, "--dry-run")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, 0, requests)
+	assert.Equal(t, "DELETE "+ts.URL+flytepath.FlowsPath+"/my-flow\nBody: none\n"+
+		"DELETE "+ts.URL+flytepath.FlowsPath+"/other-flow\nBody: none\n", output)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCmdDs() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "datastore COMMAND",
+		Aliases: []string{"ds"},
+		Short:   "Export, import, encrypt and decrypt datastore items",
+		Long:    longDs,
+		Example: exampleDs,
+	}
+
+	cmd.AddCommand(newCmdDsExport(), newCmdDsImport(), newCmdDsEncrypt(), newCmdDsDecrypt())
+	return cmd
+}
+
+const longDs = `
+Export all datastore items from a flyte API to an archive or a directory, and import them
+to another flyte API, e.g. to back up the datastore or to clone an environment.
+Encrypt and decrypt datastore item files, so secrets can be kept in a repository.`
+
+const exampleDs = `  # Copy all datastore items from staging to prod context
+  flyte ds export -o ./ds.tar.gz --context staging
+  flyte ds import -f ./ds.tar.gz --context prod
+
+  # Encrypt credentials.json to credentials.json.enc and upload it as credentials item
+  flyte ds encrypt -f ./credentials.json --key-file ./flyte.key
+  flyte upload ds -f ./credentials.json.enc --key-file ./flyte.key`
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDs_ShouldExportAndImportItemsWithArchive(t *testing.T) {
+	//given
+	staging := newFakeAPI()
+	staging.ds["env"] = fakeDsItem{value: []byte(`{"channel":"123"}`), contentType: httputil.MediaTypeJson, description: "Slack environment"}
+	staging.ds["hello"] = fakeDsItem{value: []byte("#!/bin/sh\necho hello\n"), contentType: "application/x-sh"}
+	stagingServer := httptest.NewServer(staging)
+	defer stagingServer.Close()
+
+	prod := newFakeAPI()
+	prodServer := httptest.NewServer(prod)
+	defer prodServer.Close()
+
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "ds.tar.gz")
+
+	//when
+	exported, err := executeCommand("ds", "export", "-o", archive, "--url", stagingServer.URL)
+	require.NoError(t, err)
+	imported, err := executeCommand("ds", "import", "-f", archive, "--url", prodServer.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, "2 datastore item(s) exported to "+archive+"\n", exported)
+	l := prodServer.URL + flytepath.DatastorePath
+	assert.Equal(t, "KIND       NAME   STATUS   LOCATION\n"+
+		"datastore  env    created  "+l+"/env\n"+
+		"datastore  hello  created  "+l+"/hello\n", imported)
+	assert.Equal(t, staging.ds, prod.ds)
+}
+
+func TestDs_ShouldExportItemsToDirectoryWithManifest(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.ds["env"] = fakeDsItem{value: []byte(`{"channel":"123"}`), contentType: httputil.MediaTypeJson, description: "Slack environment"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	//when
+	_, err = executeCommand("ds", "export", "-o", dir, "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	value, err := ioutil.ReadFile(filepath.Join(dir, "ds", "env"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"channel":"123"}`, string(value))
+
+	manifest, err := ioutil.ReadFile(filepath.Join(dir, manifestFile))
+	require.NoError(t, err)
+	assert.Equal(t, "datastore:\n"+
+		"- contentType: application/json\n"+
+		"  description: Slack environment\n"+
+		"  file: ds/env\n"+
+		"  name: env\n", string(manifest))
+}
+
+func TestDs_ShouldSkipExistingItemsOnImport(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.ds["env"] = fakeDsItem{value: []byte("old"), contentType: "text/plain"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("ds", "import", "-f", "./testdata/ds-export", "--url", ts.URL, "--conflict", "skip", "-o", "name")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env\ndatastore/hello\n", output)
+	assert.Equal(t, "old", string(api.ds["env"].value))
+	assert.Equal(t, "application/x-sh", api.ds["hello"].contentType)
+}
+
+func TestDs_ShouldOverwriteExistingItemsOnImport(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.ds["env"] = fakeDsItem{value: []byte("old"), contentType: "text/plain"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("ds", "import", "-f", "./testdata/ds-export", "--url", ts.URL, "--conflict", "overwrite")
+
+	//then
+	require.NoError(t, err)
+	assert.Contains(t, output, "datastore  env    updated")
+	assert.Equal(t, `{"channel":"123"}`+"\n", string(api.ds["env"].value))
+	assert.Equal(t, "Slack environment", api.ds["env"].description)
+}
+
+func TestDs_ShouldNotImportAnythingWhenItemsExist(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.ds["env"] = fakeDsItem{value: []byte("old"), contentType: "text/plain"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("ds", "import", "-f", "./testdata/ds-export", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot import: datastore item(s) already exist: env, use --conflict skip or overwrite", err.Error())
+	assert.NotContains(t, api.ds, "hello")
+}
+
+func TestDs_ShouldFailForInvalidConflictPolicy(t *testing.T) {
+	_, err := executeCommand("ds", "import", "-f", "./testdata/ds-export", "--url", "http://localhost:1", "--conflict", "merge")
+
+	require.Error(t, err)
+	assert.Equal(t, `invalid --conflict value "merge", it must be one of: skip|overwrite|fail`, err.Error())
+}
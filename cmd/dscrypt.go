@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var argsDsCrypt = struct {
+	filename   string
+	outputFile string
+	overwrite  bool
+}{}
+
+func newCmdDsEncrypt() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt -f FILENAME",
+		Short: "Encrypt a datastore item file so it can be kept in a repository",
+		Long:  longDsEncrypt,
+		Args:  cobra.NoArgs,
+		RunE:  runDsEncrypt,
+	}
+
+	cmd.Flags().StringVarP(&argsDsCrypt.filename, flagFilename, "f", "", "file to encrypt")
+	cmd.MarkFlagRequired(flagFilename)
+	cmd.Flags().StringVar(&argsDsCrypt.outputFile, flagOutputFile, "", "encrypted file (default FILENAME.enc), required when FILENAME is -")
+	cmd.Flags().BoolVar(&argsDsCrypt.overwrite, flagOverwrite, false, "replace the encrypted file if it already exists")
+	return cmd
+}
+
+const longDsEncrypt = `
+Encrypt a datastore item file with AES-256-GCM using the key from the file set by the
+--key-file option or $FLYTE_KEY_FILE. The key file contains a base64 encoded 32 bytes key,
+it can be generated e.g. by 'openssl rand -base64 32'.
+
+Encrypted files have .enc extension added to their name. They are decrypted in memory by
+upload ds, apply and diff, and their name and content type are derived from the file name
+without .enc extension, so env.json.enc is uploaded as env item with application/json type.
+An existing encrypted file is replaced only with the --overwrite option. The plain file is
+kept, remove it or add it to .gitignore so the secret is not committed. The value is read
+from stdin when FILENAME is -, the --output-file option is required then.
+
+Examples:
+  # Encrypt credentials.json to credentials.json.enc
+  flyte ds encrypt -f ./credentials.json --key-file ~/.flyte/flyte.key
+`
+
+func newCmdDsDecrypt() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt -f FILENAME",
+		Short: "Decrypt a datastore item file encrypted by 'flyte ds encrypt'",
+		Long:  longDsDecrypt,
+		Args:  cobra.NoArgs,
+		RunE:  runDsDecrypt,
+	}
+
+	cmd.Flags().StringVarP(&argsDsCrypt.filename, flagFilename, "f", "", "file to decrypt")
+	cmd.MarkFlagRequired(flagFilename)
+	cmd.Flags().StringVar(&argsDsCrypt.outputFile, flagOutputFile, "", "decrypted file (default stdout)")
+	return cmd
+}
+
+const longDsDecrypt = `
+Decrypt a datastore item file encrypted by 'flyte ds encrypt' using the key from the file
+set by the --key-file option or $FLYTE_KEY_FILE. The value is printed to stdout unless
+the --output-file option is set, so it does not have to be saved unencrypted.
+
+Examples:
+  # Print decrypted credentials
+  flyte ds decrypt -f ./credentials.json.enc --key-file ~/.flyte/flyte.key
+`
+
+func runDsEncrypt(c *cobra.Command, args []string) error {
+	if strings.HasSuffix(argsDsCrypt.filename, encryptedExt) {
+		return fmt.Errorf("cannot encrypt: %s is already encrypted", argsDsCrypt.filename)
+	}
+	if argsDsCrypt.filename == "-" && argsDsCrypt.outputFile == "" {
+		return fmt.Errorf("cannot encrypt: --%s option is required when reading from stdin", flagOutputFile)
+	}
+
+	key, err := readKey()
+	if err != nil {
+		return fmt.Errorf("cannot encrypt: %v", err)
+	}
+	value, err := readFile(argsDsCrypt.filename)
+	if err != nil {
+		return err
+	}
+	data, err := encrypt(key, value)
+	if err != nil {
+		return err
+	}
+
+	output := argsDsCrypt.outputFile
+	if output == "" {
+		output = argsDsCrypt.filename + encryptedExt
+	}
+	if _, err := os.Stat(output); err == nil && !argsDsCrypt.overwrite {
+		return fmt.Errorf("cannot encrypt: %s already exists, use --%s option to replace it", output, flagOverwrite)
+	}
+	if err := ioutil.WriteFile(output, data, 0644); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.OutOrStdout(), "%s encrypted to %s\n", argsDsCrypt.filename, output); err != nil {
+		return err
+	}
+	if argsDsCrypt.filename != "-" {
+		_, err = fmt.Fprintf(c.OutOrStderr(), "remove %s or add it to .gitignore, so the plain value is not committed\n", argsDsCrypt.filename)
+	}
+	return err
+}
+
+func runDsDecrypt(c *cobra.Command, args []string) error {
+	key, err := readKey()
+	if err != nil {
+		return fmt.Errorf("cannot decrypt: %v", err)
+	}
+	data, err := readFile(argsDsCrypt.filename)
+	if err != nil {
+		return err
+	}
+	value, err := decrypt(key, data)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt %s: %v", argsDsCrypt.filename, err)
+	}
+
+	if argsDsCrypt.outputFile == "" {
+		_, err = c.OutOrStdout().Write(value)
+		return err
+	}
+	if err := ioutil.WriteFile(argsDsCrypt.outputFile, value, 0600); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.OutOrStdout(), "%s decrypted to %s\n", argsDsCrypt.filename, argsDsCrypt.outputFile)
+	return err
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCryptDir creates a temporary directory with a key file and ds/env.json.enc file encrypted by the key
+func newCryptDir(t *testing.T) (dir, keyFile string) {
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "ds"), 0755))
+
+	keyFile = filepath.Join(dir, "flyte.key")
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(key+"\n"), 0600))
+	plain := filepath.Join(dir, "ds", "env.json")
+	require.NoError(t, ioutil.WriteFile(plain, []byte(`{"channel":"123"}`), 0644))
+
+	_, err = executeCommand("ds", "encrypt", "-f", plain, "--key-file", keyFile)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(plain))
+	return dir, keyFile
+}
+
+func TestDsCrypt_ShouldEncryptAndDecryptFile(t *testing.T) {
+	//given
+	dir, keyFile := newCryptDir(t)
+	defer os.RemoveAll(dir)
+	encrypted := filepath.Join(dir, "ds", "env.json.enc")
+
+	//when
+	output, err := executeCommand("ds", "decrypt", "-f", encrypted, "--key-file", keyFile)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, `{"channel":"123"}`, output)
+
+	data, err := ioutil.ReadFile(encrypted)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), encryptedPrefix), string(data))
+	assert.NotContains(t, string(data), "channel")
+}
+
+func TestDsCrypt_ShouldUploadDecryptedValue(t *testing.T) {
+	//given
+	dir, keyFile := newCryptDir(t)
+	defer os.RemoveAll(dir)
+
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", filepath.Join(dir, "ds", "env.json.enc"), "--key-file", keyFile, "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env created\n", output)
+	assert.Equal(t, `{"channel":"123"}`, string(api.ds["env"].value))
+	assert.Equal(t, httputil.MediaTypeJson, api.ds["env"].contentType)
+}
+
+func TestDsCrypt_ShouldApplyDecryptedValue(t *testing.T) {
+	//given
+	dir, keyFile := newCryptDir(t)
+	defer os.RemoveAll(dir)
+
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("apply", "-f", filepath.Join(dir, "ds"), "--key-file", keyFile, "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env created\n", output)
+	assert.Equal(t, `{"channel":"123"}`, string(api.ds["env"].value))
+}
+
+func TestDsCrypt_ShouldFailToDecryptWithWrongKey(t *testing.T) {
+	//given
+	dir, keyFile := newCryptDir(t)
+	defer os.RemoveAll(dir)
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 32)))
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(key), 0600))
+	encrypted := filepath.Join(dir, "ds", "env.json.enc")
+
+	//when
+	_, err := executeCommand("ds", "decrypt", "-f", encrypted, "--key-file", keyFile)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot decrypt "+encrypted+": wrong key or the file is corrupted", err.Error())
+}
+
+func TestDsCrypt_ShouldFailToUploadEncryptedFileWithoutKey(t *testing.T) {
+	//given
+	dir, _ := newCryptDir(t)
+	defer os.RemoveAll(dir)
+	encrypted := filepath.Join(dir, "ds", "env.json.enc")
+
+	//when
+	_, err := executeCommand("upload", "ds", "-f", encrypted, "--url", "http://localhost:1")
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot decrypt "+encrypted+": key file is not set, use --key-file option or $FLYTE_KEY_FILE", err.Error())
+}
+
+func TestDsCrypt_ShouldFailForInvalidKeyFile(t *testing.T) {
+	//given
+	dir, keyFile := newCryptDir(t)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("secret"), 0600))
+
+	//when
+	_, err := executeCommand("ds", "encrypt", "-f", keyFile, "--key-file", keyFile)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot encrypt: invalid key file "+keyFile+", it must contain base64 encoded 32 bytes key", err.Error())
+}
+
+func TestDsCrypt_ShouldNotPrintEncryptedValuesInDiff(t *testing.T) {
+	//given
+	dir, keyFile := newCryptDir(t)
+	defer os.RemoveAll(dir)
+
+	api := newFakeAPI()
+	api.ds["env"] = fakeDsItem{value: []byte(`{"channel":"456"}`), contentType: httputil.MediaTypeJson}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("diff", "-f", filepath.Join(dir, "ds"), "--key-file", keyFile, "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, output, "-encrypted value, sha256 ")
+	assert.Contains(t, output, "+encrypted value, sha256 ")
+	assert.NotContains(t, output, "123")
+	assert.NotContains(t, output, "456")
+}
+
+func TestDsCrypt_ShouldNotOverwriteEncryptedFileWithoutOverwriteOption(t *testing.T) {
+	//given
+	dir, keyFile := newCryptDir(t)
+	defer os.RemoveAll(dir)
+	plain := filepath.Join(dir, "ds", "env.json")
+	require.NoError(t, ioutil.WriteFile(plain, []byte(`{"channel":"456"}`), 0644))
+
+	//when
+	_, err := executeCommand("ds", "encrypt", "-f", plain, "--key-file", keyFile)
+	require.Error(t, err)
+	output, overwriteErr := executeCommand("ds", "encrypt", "-f", plain, "--key-file", keyFile, "--overwrite")
+
+	//then
+	assert.Equal(t, "cannot encrypt: "+plain+".enc already exists, use --overwrite option to replace it", err.Error())
+	require.NoError(t, overwriteErr)
+	assert.Equal(t, plain+" encrypted to "+plain+".enc\n"+
+		"remove "+plain+" or add it to .gitignore, so the plain value is not committed\n", output)
+}
+
+func TestDsCrypt_ShouldRequireOutputFileWhenEncryptingStdin(t *testing.T) {
+	//given
+	dir, keyFile := newCryptDir(t)
+	defer os.RemoveAll(dir)
+
+	//when
+	_, err := executeCommand("ds", "encrypt", "-f", "-", "--key-file", keyFile)
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot encrypt: --output-file option is required when reading from stdin", err.Error())
+	_, statErr := os.Stat("-.enc")
+	assert.True(t, os.IsNotExist(statErr))
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// dsExportDir is the directory of item values in the exported archive
+const dsExportDir = "ds"
+
+var argsDsExport = struct {
+	output string
+}{}
+
+func newCmdDsExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export -o FILENAME",
+		Short: "Export all datastore items to an archive or a directory",
+		Long:  longDsExport,
+		Args:  cobra.NoArgs,
+		RunE:  runDsExport,
+	}
+
+	cmd.Flags().StringVarP(&argsDsExport.output, flagOutput, "o", "", "tar.gz archive or directory to export the items to, it overrides the global output format option")
+	cmd.MarkFlagRequired(flagOutput)
+	return cmd
+}
+
+const longDsExport = `
+Export all datastore items from a flyte API with their names, descriptions and content types.
+Items are exported to a tar.gz archive when the filename ends with .tar.gz or .tgz, otherwise
+to a directory. Values are saved to ds/NAME files as they are stored in the datastore, and the
+items are listed in flyte-manifest.yaml, so an exported directory can be used by apply too.
+
+Examples:
+  # Export all datastore items to an archive
+  flyte ds export -o ./ds.tar.gz
+
+  # Export all datastore items of staging context to a directory
+  flyte ds export -o ./ds --context staging
+`
+
+func runDsExport(c *cobra.Command, args []string) error {
+	var list dsList
+	if err := getJSON(dsURL(apiURL()), &list); err != nil {
+		return fmt.Errorf("cannot list datastore items\n%s", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	w, err := newArchiveWriter(argsDsExport.output)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	exported, err := exportDs(w, apiURL(), list.Items, dsExportDir)
+	if err != nil {
+		return err
+	}
+	if err := addYAML(w, manifestFile, applyManifest{Datastore: exported}); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.OutOrStdout(), "%d datastore item(s) exported to %s\n", len(list.Items), argsDsExport.output)
+	return err
+}
+
+// exportDs downloads the items' values to the directory in the archive, it returns the exported items
+func exportDs(w archiveWriter, apiURL string, items []dsSummary, dir string) ([]manifestItem, error) {
+	var exported []manifestItem
+	for _, i := range items {
+		value, contentType, err := getDatastoreValue(dsItemURL(apiURL, i.Name))
+		if err != nil {
+			return nil, fmt.Errorf("cannot export datastore/%s\n%s", i.Name, err)
+		}
+		if i.ContentType != "" {
+			contentType = i.ContentType
+		}
+
+		file := dir + "/" + i.Name
+		if err := w.add(file, value); err != nil {
+			return nil, err
+		}
+		exported = append(exported, manifestItem{
+			File:        file,
+			Name:        i.Name,
+			Description: i.Description,
+			ContentType: contentType,
+		})
+	}
+	return exported, nil
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictFail      = "fail"
+)
+
+var argsDsImport = struct {
+	filename string
+	conflict string
+}{}
+
+func newCmdDsImport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import -f FILENAME",
+		Short: "Import datastore items from an archive or a directory",
+		Long:  longDsImport,
+		Args:  cobra.NoArgs,
+		RunE:  runDsImport,
+	}
+
+	cmd.Flags().StringVarP(&argsDsImport.filename, flagFilename, "f", "", "tar.gz archive or directory exported by 'flyte ds export'")
+	cmd.MarkFlagRequired(flagFilename)
+
+	cmd.Flags().StringVar(&argsDsImport.conflict, flagConflict, conflictFail, "what to do with items which already exist in flyte API. One of: skip|overwrite|fail")
+	return cmd
+}
+
+const longDsImport = `
+Import datastore items exported by 'flyte ds export' to a flyte API, with their names,
+descriptions and content types.
+
+Items which already exist in the flyte API are handled by the --conflict option:
+  skip       existing items are left unchanged and reported as skipped
+  overwrite  existing items are replaced and reported as updated
+  fail       nothing is imported when any of the items exists (default)
+
+A table with the result of every item is printed, or the results are printed in the format
+set by the --output option. With the --dry-run option the requests are printed instead of sent.
+
+Examples:
+  # Import datastore items to prod context, items which already exist are skipped
+  flyte ds import -f ./ds.tar.gz --context prod --conflict skip
+`
+
+func runDsImport(c *cobra.Command, args []string) error {
+	if err := checkOutput(); err != nil {
+		return err
+	}
+	if err := checkConflict(argsDsImport.conflict); err != nil {
+		return err
+	}
+
+	files, err := readArchive(argsDsImport.filename)
+	if err != nil {
+		return err
+	}
+	data, ok := files[manifestFile]
+	if !ok {
+		return fmt.Errorf("cannot import: %s is missing in %s", manifestFile, argsDsImport.filename)
+	}
+	manifest := applyManifest{}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("cannot read %s: %v", manifestFile, err)
+	}
+	items, err := archivedDsItems(files, manifest.Datastore, argsDsImport.filename)
+	if err != nil {
+		return err
+	}
+
+	existing, err := listDsItems(apiURL())
+	if err != nil {
+		return err
+	}
+	failed, err := importDs(c, apiURL(), items, existing, argsDsImport.conflict)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("cannot import %d of %d datastore item(s)", failed, len(items))
+	}
+	return nil
+}
+
+func checkConflict(conflict string) error {
+	switch conflict {
+	case conflictSkip, conflictOverwrite, conflictFail:
+		return nil
+	}
+	return fmt.Errorf("invalid --%s value %q, it must be one of: skip|overwrite|fail", flagConflict, conflict)
+}
+
+// archivedDsItems reads values of the items from the archive's files
+func archivedDsItems(files map[string][]byte, archived []manifestItem, filename string) ([]dsItem, error) {
+	var items []dsItem
+	for _, i := range archived {
+		if i.Name == "" {
+			return nil, fmt.Errorf("cannot import datastore item from %s: name is missing", i.File)
+		}
+		value, ok := files[path.Clean(i.File)]
+		if !ok {
+			return nil, fmt.Errorf("cannot import datastore/%s: %s is missing in %s", i.Name, i.File, filename)
+		}
+		if value == nil {
+			value = []byte{}
+		}
+
+		items = append(items, dsItem{
+			name:        i.Name,
+			description: i.Description,
+			contentType: i.ContentType,
+			filename:    i.File,
+			value:       value,
+		})
+	}
+	return items, nil
+}
+
+// importDs uploads the items, the existing ones are handled according to the conflict policy.
+// It returns the number of items which cannot be uploaded, on dry run the requests are printed.
+func importDs(c *cobra.Command, apiURL string, items []dsItem, existing map[string]dsSummary, conflict string) (int, error) {
+	var conflicts []string
+	for _, i := range items {
+		if _, ok := existing[i.name]; ok {
+			conflicts = append(conflicts, i.name)
+		}
+	}
+	if conflict == conflictFail && len(conflicts) > 0 {
+		return 0, fmt.Errorf("cannot import: datastore item(s) already exist: %s, use --%s skip or overwrite",
+			strings.Join(conflicts, ", "), flagConflict)
+	}
+
+	results := make([]uploadResult, len(items))
+	errs := make([]error, len(items))
+	var upload []dsItem
+	var uploadIndex []int
+	for i, item := range items {
+		if _, ok := existing[item.name]; ok && conflict == conflictSkip {
+			results[i] = uploadResult{Kind: kindDatastore, Name: item.name, Location: dsItemURL(apiURL, item.name), Status: statusSkipped}
+			continue
+		}
+		upload = append(upload, item)
+		uploadIndex = append(uploadIndex, i)
+	}
+
+	if isDryRun() {
+		for _, item := range upload {
+			req, err := newDsRequest(apiURL, item)
+			if err != nil {
+				return 0, err
+			}
+			if err := printRequest(c, req); err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	}
+
+	uploaded, uploadErrs := uploadDsItems(c, apiURL, upload, defaultConcurrency)
+	for j, i := range uploadIndex {
+		results[i], errs[i] = uploaded[j], uploadErrs[j]
+	}
+	return reportDsResults(c, items, results, errs)
+}
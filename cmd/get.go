@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCmdGet() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get TYPE",
+		Short:   "Display one or many resources from a flyte API",
+		Long:    longGet,
+		Example: exampleGet,
+	}
+
+	cmd.SetUsageTemplate(usageTmplResource)
+	cmd.AddCommand(newCmdGetFlow(), newCmdGetDs(), newCmdGetPack())
+	return cmd
+}
+
+const longGet = `
+
+Display one or many resources from a flyte API. Valid resource types include:
+
+  * datastore (aka 'ds')
+  * flow (aka 'flows')
+  * pack (aka 'packs')`
+
+const exampleGet = `  # List all flows from flyte API specified by $FLYTE_API
+  flyte get flows
+
+  # Get a single flow as yaml and save it to a file
+  flyte get flow my-flow -o yaml > ./my-flow.yaml`
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"text/tabwriter"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/spf13/cobra"
+)
+
+var argsGetDs = struct {
+	format string
+	output string
+}{}
+
+func newCmdGetDs() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "datastore [NAME]",
+		Aliases: []string{"ds"},
+		Short:   "List all datastore items or download a single item's value",
+		Long:    longGetDs,
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    runGetDs,
+	}
+
+	cmd.Flags().StringVar(&argsGetDs.format, flagFormat, "", "Output format of the list. One of: json|yaml (default table)")
+	cmd.Flags().MarkDeprecated(flagFormat, "use -o/--output instead")
+	cmd.Flags().StringVarP(&argsGetDs.output, flagOutput, "o", "", "file to save the item's value to (default stdout), or format of the list. One of: json|yaml (default table). It overrides the global output format option")
+	return cmd
+}
+
+const longGetDs = `
+List all datastore items or download a single item's value from a flyte API.
+Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
+
+A list shows item's name, content type and description, or it is printed in json or yaml
+format set by the -o option. A single item's value is downloaded as it is stored in the
+datastore, without any conversion, to stdout or to the file set by the -o option.
+
+Examples:
+  # List all datastore items
+  flyte get ds
+
+  # Print env datastore item's value
+  flyte get ds env
+
+  # Save env datastore item's value to a file
+  flyte get ds env -o ./env.json
+`
+
+type dsList struct {
+	Items []dsSummary `json:"datastore"`
+}
+
+type dsSummary struct {
+	Name        string `json:"key"`
+	ContentType string `json:"contentType,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func runGetDs(c *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return listDs(c)
+	}
+	return getDs(c, args[0])
+}
+
+func listDs(c *cobra.Command) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+
+	var list dsList
+	if err := getJSON(dsURL(apiURL()), &list); err != nil {
+		return fmt.Errorf("cannot list datastore items\n%s", err)
+	}
+
+	if format != "" {
+		return printMarshalled(c, list.Items, format)
+	}
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCONTENT TYPE\tDESCRIPTION")
+	for _, i := range list.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", i.Name, i.ContentType, i.Description)
+	}
+	return w.Flush()
+}
+
+func getDs(c *cobra.Command, name string) error {
+	value, contentType, err := getDatastoreValue(dsItemURL(apiURL(), name))
+	if err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("cannot get datastore item: %s not found", name)
+		}
+		return fmt.Errorf("cannot get datastore item\n%s", err)
+	}
+
+	if argsGetDs.output == "" {
+		_, err = c.OutOrStdout().Write(value)
+		return err
+	}
+
+	if err := ioutil.WriteFile(argsGetDs.output, value, 0644); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.OutOrStdout(), "datastore item %q (%s) saved to %s\n", name, contentType, argsGetDs.output)
+	return err
+}
+
+func dsURL(apiURL string) string {
+	return fmt.Sprintf("%s%s", apiURL, flytepath.DatastorePath)
+}
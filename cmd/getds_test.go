@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDs_ShouldListItemsAsTable(t *testing.T) {
+	//given
+	rec := requestRec{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.request = *r
+		w.Header().Set(httputil.HeaderContentType, httputil.MediaTypeJson)
+		fmt.Fprint(w, `{"datastore":[{"key":"env","contentType":"application/json","description":"Environments"},{"key":"upload","contentType":"application/x-sh"}]}`)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "ds", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, flytepath.DatastorePath, rec.request.URL.String())
+	assert.Equal(t, "NAME    CONTENT TYPE      DESCRIPTION\nenv     application/json  Environments\nupload  application/x-sh  \n", output)
+}
+
+func TestGetDs_ShouldListItemsInOutputFormat(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"datastore":[{"key":"env","contentType":"application/json"}]}`)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "ds", "-o", "yaml", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, "- contentType: application/json\n  key: env\n\n", output)
+}
+
+func TestGetDs_ShouldPrintRawItemValue(t *testing.T) {
+	//given
+	rec := requestRec{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.request = *r
+		w.Header().Set(httputil.HeaderContentType, httputil.MediaTypeJson)
+		fmt.Fprint(w, `{"flyte":{"status":"All good"}}`)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "ds", "env", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, flytepath.DatastorePath+"/env", rec.request.URL.String())
+	assert.Equal(t, `{"flyte":{"status":"All good"}}`, output)
+}
+
+func TestGetDs_ShouldSaveItemValueToFile(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(httputil.HeaderContentType, "application/x-sh")
+		fmt.Fprint(w, `echo hello`)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "upload.sh")
+
+	//when
+	output, err := executeCommand("get", "ds", "upload", "-o", file, "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	value, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "echo hello", string(value))
+	assert.Equal(t, fmt.Sprintf("datastore item \"upload\" (application/x-sh) saved to %s\n", file), output)
+}
+
+func TestGetDs_ShouldFailWhenItemNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	_, err := executeCommand("get", "ds", "env", "--url", ts.URL)
+	require.Error(t, err)
+
+	assert.Equal(t, "cannot get datastore item: env not found", err.Error())
+}
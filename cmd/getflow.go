@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var argsGetFlow = struct {
+	format string
+}{}
+
+func newCmdGetFlow() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "flow [NAME]",
+		Aliases: []string{"flows"},
+		Short:   "List all flows or get a single flow",
+		Long:    longGetFlow,
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    runGetFlow,
+	}
+
+	cmd.Flags().StringVar(&argsGetFlow.format, flagFormat, "", "Output format. One of: json|yaml (default table for a list of flows and json for a single flow)")
+	cmd.Flags().MarkDeprecated(flagFormat, "use -o/--output instead")
+	return cmd
+}
+
+const longGetFlow = `
+List all flows or get a single flow by name from a flyte API.
+Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
+
+A list is printed as a table and a single flow as json, unless the --output option sets json
+or yaml format. A single flow is printed without API links so it can be saved to a file and
+uploaded again.
+
+Examples:
+  # List all flows
+  flyte get flows
+
+  # Get my-flow as json
+  flyte get flow my-flow
+
+  # Get my-flow as yaml and save it to a file
+  flyte get flow my-flow -o yaml > ./my-flow.yaml
+`
+
+type flowList struct {
+	Flows []flowSummary `json:"flows"`
+}
+
+type flowSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+func runGetFlow(c *cobra.Command, args []string) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return listFlows(c, format)
+	}
+	return getFlow(c, args[0], format)
+}
+
+func listFlows(c *cobra.Command, format string) error {
+	var list flowList
+	if err := getJSON(flowsURL(apiURL()), &list); err != nil {
+		return fmt.Errorf("cannot list flows\n%s", err)
+	}
+
+	if format != "" {
+		return printMarshalled(c, list.Flows, format)
+	}
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDESCRIPTION")
+	for _, f := range list.Flows {
+		fmt.Fprintf(w, "%s\t%s\n", f.Name, f.Description)
+	}
+	return w.Flush()
+}
+
+func getFlow(c *cobra.Command, name, format string) error {
+	flow, err := fetchFlow(flowURL(apiURL(), name))
+	if err != nil {
+		return fmt.Errorf("cannot get flow\n%s", err)
+	}
+	if flow == nil {
+		return fmt.Errorf("cannot get flow: %s not found", name)
+	}
+	return printMarshalled(c, flow, format)
+}
+
+func printMarshalled(c *cobra.Command, v interface{}, format string) error {
+	out, err := marshal(v, format)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(c.OutOrStdout(), string(out))
+	return err
+}
+
+func flowURL(apiURL, name string) string {
+	return fmt.Sprintf("%s/%s", flowsURL(apiURL), name)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFlow_ShouldListFlowsAsTable(t *testing.T) {
+	//given
+	rec := requestRec{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.request = *r
+		w.Header().Set(httputil.HeaderContentType, httputil.MediaTypeJson)
+		fmt.Fprint(w, `{"flows":[{"name":"my-flow","description":"My awesome flow","links":[]},{"name":"other-flow"}]}`)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "flows", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, flytepath.FlowsPath, rec.request.URL.String())
+	assert.Equal(t, http.MethodGet, rec.request.Method)
+	assert.Equal(t, "NAME        DESCRIPTION\nmy-flow     My awesome flow\nother-flow  \n", output)
+}
+
+func TestGetFlow_ShouldGetFlowAsYamlWithoutLinks(t *testing.T) {
+	//given
+	rec := requestRec{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.request = *r
+		w.Header().Set(httputil.HeaderContentType, httputil.MediaTypeJson)
+		fmt.Fprint(w, `{"name":"my-flow","description":"My awesome flow","steps":[],"links":[{"href":"http://x","rel":"self"}]}`)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "flow", "my-flow", "-o", "yaml", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, flytepath.FlowsPath+"/my-flow", rec.request.URL.String())
+	assert.Equal(t, "description: My awesome flow\nname: my-flow\nsteps: []\n\n", output)
+}
+
+func TestGetFlow_ShouldFailWhenFlowNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	_, err := executeCommand("get", "flow", "my-flow", "--url", ts.URL)
+	require.Error(t, err)
+
+	assert.Equal(t, "cannot get flow: my-flow not found", err.Error())
+}
+
+func TestGetFlow_ShouldFailWhenFlyteAPIReturnsNon200(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	_, err := executeCommand("get", "flows", "--url", ts.URL)
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "cannot list flows\nHTTP/1.1 500 Internal Server Error")
+}
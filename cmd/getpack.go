@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/spf13/cobra"
+)
+
+var argsGetPack = struct {
+	format string
+}{}
+
+func newCmdGetPack() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pack",
+		Aliases: []string{"packs"},
+		Short:   "List all registered packs",
+		Long:    longGetPack,
+		Args:    cobra.NoArgs,
+		RunE:    runGetPack,
+	}
+
+	cmd.Flags().StringVar(&argsGetPack.format, flagFormat, "", "Output format. One of: json|yaml (default table)")
+	cmd.Flags().MarkDeprecated(flagFormat, "use -o/--output instead")
+	return cmd
+}
+
+const longGetPack = `
+List all packs registered in a flyte API with their labels and status.
+Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
+
+Examples:
+  # List all packs
+  flyte get packs
+`
+
+type packList struct {
+	Packs []pack `json:"packs"`
+}
+
+type pack struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Status   string            `json:"status,omitempty"`
+	Events   []packEvent       `json:"events,omitempty"`
+	Commands []packCommand     `json:"commands,omitempty"`
+}
+
+type packEvent struct {
+	Name string `json:"name"`
+}
+
+type packCommand struct {
+	Name   string   `json:"name"`
+	Events []string `json:"events,omitempty"`
+}
+
+func runGetPack(c *cobra.Command, args []string) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	packs, err := listPacks(apiURL())
+	if err != nil {
+		return err
+	}
+
+	if format != "" {
+		return printMarshalled(c, packs, format)
+	}
+
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tLABELS\tSTATUS")
+	for _, p := range packs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.ID, p.Name, formatLabels(p.Labels), p.Status)
+	}
+	return w.Flush()
+}
+
+func listPacks(apiURL string) ([]pack, error) {
+	var list packList
+	if err := getJSON(packsURL(apiURL), &list); err != nil {
+		return nil, fmt.Errorf("cannot list packs\n%s", err)
+	}
+	return list.Packs, nil
+}
+
+// formatLabels formats labels as comma separated key=value pairs sorted by keys
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func packsURL(apiURL string) string {
+	return fmt.Sprintf("%s%s", apiURL, flytepath.PacksPath)
+}
+
+func packURL(apiURL, id string) string {
+	return fmt.Sprintf("%s/%s", packsURL(apiURL), id)
+}
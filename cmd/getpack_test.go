@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPack_ShouldListPacksAsTable(t *testing.T) {
+	//given
+	rec := requestRec{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.request = *r
+		w.Header().Set(httputil.HeaderContentType, httputil.MediaTypeJson)
+		fmt.Fprint(w, packsResponse)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "packs", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, flytepath.PacksPath, rec.request.URL.String())
+	assert.Equal(t, "ID              NAME   LABELS             STATUS\n"+
+		"Slack           Slack                     live\n"+
+		"Shell.env:prod  Shell  env=prod,team=ops  warning\n", output)
+}
+
+func TestGetPack_ShouldListPacksAsJson(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packs":[{"id":"Slack","name":"Slack","status":"live"}]}`)
+	}))
+	defer ts.Close()
+
+	output, err := executeCommand("get", "packs", "-o", "json", "--url", ts.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "[\n\t{\n\t\t\"id\": \"Slack\",\n\t\t\"name\": \"Slack\",\n\t\t\"status\": \"live\"\n\t}\n]\n", output)
+}
+
+const packsResponse = `{"packs":[
+	{"id":"Slack","name":"Slack","status":"live","links":[]},
+	{"id":"Shell.env:prod","name":"Shell","labels":{"team":"ops","env":"prod"},"status":"warning"}
+]}`
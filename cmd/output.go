@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	httputl "net/http/httputil"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+	outputName = "name"
+	outputWide = "wide"
+)
+
+// uploadResult is the outcome of an upload printed in the format requested by the --output option
+type uploadResult struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	Status   string `json:"status"`
+}
+
+// newUploadResult creates the result from the response, the location defaults to the request URL
+func newUploadResult(kind, name string, resp *http.Response) uploadResult {
+	location := resp.Request.URL.String()
+	if l, err := resp.Location(); err == nil {
+		location = l.String()
+	}
+
+	status := statusCreated
+	if resp.StatusCode != http.StatusCreated {
+		status = statusUpdated
+	}
+	return uploadResult{Kind: kind, Name: name, Location: location, Status: status}
+}
+
+// checkOutput fails fast for unknown --output value, before any request is sent
+func checkOutput() error {
+	switch viper.GetString(flagOutput) {
+	case "", outputJSON, outputYAML, outputName, outputWide:
+		return nil
+	}
+	return fmt.Errorf("invalid --%s value %q, it must be one of: json|yaml|name|wide", flagOutput, viper.GetString(flagOutput))
+}
+
+// outputFormat is the json or yaml format of the resources printed by get, describe and test commands.
+// It is set by the --output option or by the deprecated --format option, the context's default output
+// format is used otherwise, and empty format is left to the command's default.
+func outputFormat(c *cobra.Command) (string, error) {
+	format := viper.GetString(flagOutput)
+	// get ds has its own --output option which shadows the global one
+	if f := c.Flags().Lookup(flagOutput); f != nil && f.Changed {
+		format = f.Value.String()
+	}
+	if f := c.Flags().Lookup(flagFormat); f != nil && f.Changed {
+		format = f.Value.String()
+	}
+	if format == "" {
+		format = activeContext.Output
+	}
+
+	switch format {
+	case "", outputJSON, outputYAML:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid --%s value %q, it must be one of: json|yaml", flagOutput, format)
+}
+
+func printUploadResult(c *cobra.Command, result uploadResult) error {
+	switch viper.GetString(flagOutput) {
+	case outputJSON, outputYAML:
+		return printMarshalled(c, result, viper.GetString(flagOutput))
+	}
+	return printUploadResults(c, []uploadResult{result})
+}
+
+// printUploadResults prints results of many uploads, json and yaml output is a list of the results
+func printUploadResults(c *cobra.Command, results []uploadResult) error {
+	out := c.OutOrStdout()
+	switch viper.GetString(flagOutput) {
+	case outputJSON, outputYAML:
+		return printMarshalled(c, results, viper.GetString(flagOutput))
+	case outputName:
+		for _, r := range results {
+			fmt.Fprintf(out, "%s/%s\n", r.Kind, r.Name)
+		}
+		return nil
+	case outputWide:
+		return printResultTable(c, results)
+	default:
+		for _, r := range results {
+			fmt.Fprintf(out, "%s/%s %s\n", r.Kind, r.Name, r.Status)
+		}
+		return nil
+	}
+}
+
+// printResultTable prints results as a table with a row per result
+func printResultTable(c *cobra.Command, results []uploadResult) error {
+	w := tabwriter.NewWriter(c.OutOrStdout(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tSTATUS\tLOCATION")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Kind, r.Name, r.Status, r.Location)
+	}
+	return w.Flush()
+}
+
+// printVerbose prints the raw response to stderr when --verbose option is set, so it does not mix with the result
+func printVerbose(c *cobra.Command, resp *http.Response) error {
+	if !viper.GetBool(flagVerbose) {
+		return nil
+	}
+
+	dump, err := httputl.DumpResponse(resp, true)
+	if err != nil {
+		return err
+	}
+	_, err = c.OutOrStderr().Write(dump)
+	return err
+}
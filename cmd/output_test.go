@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUploadServer(status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", flytepath.FlowsPath+"/my-flow")
+		}
+		w.WriteHeader(status)
+	}))
+}
+
+func TestOutput_ShouldPrintUploadResultAsYaml(t *testing.T) {
+	//given
+	ts := newUploadServer(http.StatusCreated)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.yaml", "--url", ts.URL, "-o", "yaml")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "kind: flow\nlocation: "+ts.URL+flytepath.FlowsPath+"/my-flow\nname: my-flow\nstatus: created\n\n", output)
+}
+
+func TestOutput_ShouldPrintUploadResultName(t *testing.T) {
+	//given
+	ts := newUploadServer(http.StatusNoContent)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", "./testdata/env.json", "--url", ts.URL, "--output", "name")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env\n", output)
+}
+
+func TestOutput_ShouldPrintUploadResultWide(t *testing.T) {
+	//given
+	ts := newUploadServer(http.StatusNoContent)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", "./testdata/env.json", "--url", ts.URL, "-o", "wide")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "KIND       NAME  STATUS   LOCATION\n"+
+		"datastore  env   updated  "+ts.URL+flytepath.DatastorePath+"/env\n", output)
+}
+
+func TestOutput_ShouldPrintRawResponseWhenVerbose(t *testing.T) {
+	//given
+	ts := newUploadServer(http.StatusCreated)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.json", "--url", ts.URL, "-v")
+
+	//then
+	require.NoError(t, err)
+	assert.Contains(t, output, "HTTP/1.1 201 Created\r\n")
+	assert.Contains(t, output, "Location: "+flytepath.FlowsPath+"/my-flow\r\n")
+	assert.Contains(t, output, "flow/my-flow created\n")
+}
+
+func TestOutput_ShouldFailForInvalidOutputBeforeUpload(t *testing.T) {
+	//given
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("upload", "ds", "-f", "./testdata/env.json", "--url", ts.URL, "-o", "xml")
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "invalid --output value \"xml\", it must be one of: json|yaml|name|wide", err.Error())
+	assert.Equal(t, 0, requests)
+}
+
+func TestOutput_ShouldPreferOutputOptionToContextOutput(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"my-flow","steps":[]}`)
+	}))
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n  output: yaml\n", ts.URL))
+	defer cleanup()
+
+	//when
+	output, err := executeCommand("get", "flow", "my-flow", "--config", config, "-o", "json")
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, "{\n\t\"name\": \"my-flow\",\n\t\"steps\": []\n}\n", output)
+}
+
+func TestOutput_ShouldAcceptDeprecatedFormatOption(t *testing.T) {
+	//given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"my-flow","steps":[]}`)
+	}))
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "flow", "my-flow", "--url", ts.URL, "--format", "yaml")
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, "Flag --format has been deprecated, use -o/--output instead\nname: my-flow\nsteps: []\n\n", output)
+}
+
+func TestOutput_ShouldFailForOutputWhichIsNotJsonOrYamlBeforeGet(t *testing.T) {
+	//given
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL, "-o", "name")
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "invalid --output value \"name\", it must be one of: json|yaml", err.Error())
+	assert.Equal(t, 0, requests)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// pruneFilter limits pruned resources by name prefixes and by labels of packs used by flow steps
+type pruneFilter struct {
+	prefixes []string
+	selector map[string]string
+}
+
+// prune deletes flows and datastore items in flyte API which are not among the resources and match the filter.
+// Nothing is deleted on dry run. Resources which cannot be pruned are returned as errors.
+func prune(apiURL string, resources []resource, items map[string]dsSummary, filter pruneFilter, dryRun bool) ([]uploadResult, []error) {
+	local := map[string]bool{}
+	for _, r := range resources {
+		local[r.kind+"/"+r.name] = true
+	}
+
+	var flows flowList
+	if err := getJSON(flowsURL(apiURL), &flows); err != nil {
+		return nil, []error{fmt.Errorf("cannot list flows\n%s", err)}
+	}
+
+	var results []uploadResult
+	var errs []error
+	for _, f := range flows.Flows {
+		if local[kindFlow+"/"+f.Name] || !filter.matchName(f.Name) {
+			continue
+		}
+
+		url := flowURL(apiURL, f.Name)
+		if len(filter.selector) > 0 {
+			var flow flowDef
+			if err := getJSON(url, &flow); err != nil {
+				errs = append(errs, fmt.Errorf("cannot prune %s/%s\n%s", kindFlow, f.Name, err))
+				continue
+			}
+			if !filter.matchFlow(flow) {
+				continue
+			}
+		}
+
+		if result, err := pruneResource(kindFlow, f.Name, url, dryRun); err != nil {
+			errs = append(errs, err)
+		} else {
+			results = append(results, result)
+		}
+	}
+
+	// datastore items have no labels
+	if len(filter.selector) > 0 {
+		return results, errs
+	}
+
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if local[kindDatastore+"/"+name] || !filter.matchName(name) {
+			continue
+		}
+		if result, err := pruneResource(kindDatastore, name, dsItemURL(apiURL, name), dryRun); err != nil {
+			errs = append(errs, err)
+		} else {
+			results = append(results, result)
+		}
+	}
+	return results, errs
+}
+
+func pruneResource(kind, name, url string, dryRun bool) (uploadResult, error) {
+	if !dryRun {
+		if err := deleteResource(url); err != nil && !isNotFound(err) {
+			return uploadResult{}, fmt.Errorf("cannot prune %s/%s\n%s", kind, name, err)
+		}
+	}
+	return uploadResult{Kind: kind, Name: name, Location: url, Status: dryRunStatus(statusPruned, dryRun)}, nil
+}
+
+func (f pruneFilter) matchName(name string) bool {
+	if len(f.prefixes) == 0 {
+		return true
+	}
+	for _, p := range f.prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFlow is true when an event or a command of any step is of a pack matching the selector
+func (f pruneFilter) matchFlow(flow flowDef) bool {
+	for _, s := range flow.Steps {
+		if labelsMatch(f.selector, s.Event.PackLabels) || labelsMatch(f.selector, s.Command.PackLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSelector parses labels in key=value[,key=value] format
+func parseSelector(selector string) (map[string]string, error) {
+	labels := map[string]string{}
+	if selector == "" {
+		return labels, nil
+	}
+
+	for _, pair := range strings.Split(selector, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid selector %q, it must be in key=value[,key=value] format", selector)
+		}
+		labels[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return labels, nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPruneAPI(t *testing.T) (*fakeAPI, *httptest.Server) {
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+
+	_, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL)
+	require.NoError(t, err)
+
+	api.flows["ops-restart"] = map[string]interface{}{"name": "ops-restart", "steps": []interface{}{map[string]interface{}{
+		"id":      "restart",
+		"event":   map[string]interface{}{"packName": "Slack", "name": "ReceivedMessage"},
+		"command": map[string]interface{}{"packName": "Shell", "packLabels": map[string]string{"env": "prod"}, "name": "Run"},
+	}}}
+	api.flows["dev-hello"] = map[string]interface{}{"name": "dev-hello", "steps": []interface{}{map[string]interface{}{
+		"id":      "hello",
+		"event":   map[string]interface{}{"packName": "Slack", "packLabels": map[string]string{"env": "dev"}, "name": "ReceivedMessage"},
+		"command": map[string]interface{}{"packName": "Slack", "name": "SendMessage"},
+	}}}
+	api.ds["ops-env"] = fakeDsItem{value: []byte("x"), contentType: "text/plain"}
+	return api, ts
+}
+
+func TestPrune_ShouldDeleteResourcesNotInDirectory(t *testing.T) {
+	//given
+	api, ts := newPruneAPI(t)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL, "--prune")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env unchanged\n"+
+		"datastore/hello unchanged\n"+
+		"flow/deploy unchanged\n"+
+		"flow/status unchanged\n"+
+		"flow/dev-hello pruned\n"+
+		"flow/ops-restart pruned\n"+
+		"datastore/ops-env pruned\n", output)
+	assert.NotContains(t, api.flows, "ops-restart")
+	assert.NotContains(t, api.flows, "dev-hello")
+	assert.NotContains(t, api.ds, "ops-env")
+	assert.Contains(t, api.flows, "status")
+}
+
+func TestPrune_ShouldDeleteOnlyResourcesWithPrefix(t *testing.T) {
+	//given
+	api, ts := newPruneAPI(t)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL, "--prune", "--prefix", "ops-", "-o", "name")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env\ndatastore/hello\nflow/deploy\nflow/status\nflow/ops-restart\ndatastore/ops-env\n", output)
+	assert.Contains(t, api.flows, "dev-hello")
+}
+
+func TestPrune_ShouldDeleteOnlyFlowsMatchingSelector(t *testing.T) {
+	//given
+	api, ts := newPruneAPI(t)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL, "--prune", "-l", "env=prod")
+
+	//then
+	require.NoError(t, err)
+	assert.Contains(t, output, "flow/ops-restart pruned\n")
+	assert.NotContains(t, output, "dev-hello")
+	assert.Contains(t, api.flows, "dev-hello")
+	assert.Contains(t, api.ds, "ops-env")
+}
+
+func TestPrune_ShouldNotChangeAnythingOnDryRun(t *testing.T) {
+	//given
+	api, ts := newPruneAPI(t)
+	defer ts.Close()
+	delete(api.flows, "deploy")
+	api.requests = nil
+
+	//when
+	output, err := executeCommand("apply", "-f", "./testdata/apply", "--url", ts.URL, "--prune", "--prefix", "ops-", "--dry-run")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env unchanged (dry run)\n"+
+		"datastore/hello unchanged (dry run)\n"+
+		"flow/deploy created (dry run)\n"+
+		"flow/status unchanged (dry run)\n"+
+		"flow/ops-restart pruned (dry run)\n"+
+		"datastore/ops-env pruned (dry run)\n", output)
+	for _, r := range api.requests {
+		assert.Regexp(t, "^GET ", r)
+	}
+}
+
+func TestPrune_ShouldFailForInvalidSelector(t *testing.T) {
+	_, err := executeCommand("apply", "-f", "./testdata/apply", "--url", "http://localhost:1", "--prune", "-l", "env")
+
+	require.Error(t, err)
+	assert.Equal(t, "invalid selector \"env\", it must be in key=value[,key=value] format", err.Error())
+}
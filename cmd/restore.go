@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+var argsRestore = struct {
+	filename string
+	conflict string
+}{}
+
+func newCmdRestore() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore -f FILENAME",
+		Short: "Restore flows and datastore items from a backup",
+		Long:  longRestore,
+		Args:  cobra.NoArgs,
+		RunE:  runRestore,
+	}
+
+	cmd.Flags().StringVarP(&argsRestore.filename, flagFilename, "f", "", "tar.gz archive or directory created by 'flyte backup'")
+	cmd.MarkFlagRequired(flagFilename)
+
+	cmd.Flags().StringVar(&argsRestore.conflict, flagConflict, conflictFail, "what to do with flows and items which already exist in flyte API. One of: skip|overwrite|fail")
+	return cmd
+}
+
+const longRestore = `
+Restore flows and datastore items from a backup created by 'flyte backup' to a flyte API,
+e.g. to recover it or to clone it to another environment. Packs are not restored, they
+register themselves when they are started.
+
+Flows and datastore items which already exist in the flyte API are handled by the --conflict option:
+  skip       existing resources are left unchanged and reported as skipped
+  overwrite  existing resources are replaced and reported as updated, flows are replaced
+             by deleting and creating them again
+  fail       nothing is restored when any of the resources exists (default)
+
+A table with the result of every resource is printed, or the results are printed in the format
+set by the --output option. With the --dry-run option only the plan is printed, every resource
+is reported with its status followed by "(dry run)" and nothing is changed.
+
+Examples:
+  # Preview what would be restored to staging context
+  flyte restore -f ./prod.tar.gz --context staging --conflict overwrite --dry-run
+
+  # Restore the backup, resources which already exist are skipped
+  flyte restore -f ./prod.tar.gz --context staging --conflict skip
+`
+
+func runRestore(c *cobra.Command, args []string) error {
+	if err := checkOutput(); err != nil {
+		return err
+	}
+	if err := checkConflict(argsRestore.conflict); err != nil {
+		return err
+	}
+
+	flows, items, err := readBackup(argsRestore.filename)
+	if err != nil {
+		return err
+	}
+
+	var list flowList
+	if err := getJSON(flowsURL(apiURL()), &list); err != nil {
+		return fmt.Errorf("cannot list flows\n%s", err)
+	}
+	existingFlows := map[string]bool{}
+	for _, f := range list.Flows {
+		existingFlows[f.Name] = true
+	}
+	existingDs, err := listDsItems(apiURL())
+	if err != nil {
+		return err
+	}
+
+	// datastore items go first, the same way as by apply
+	var plan []uploadResult
+	var conflicts []string
+	for _, i := range items {
+		_, exists := existingDs[i.name]
+		plan = append(plan, restoreStatus(kindDatastore, i.name, dsItemURL(apiURL(), i.name), exists, argsRestore.conflict, &conflicts))
+	}
+	for _, f := range flows {
+		plan = append(plan, restoreStatus(kindFlow, f.name, flowURL(apiURL(), f.name), existingFlows[f.name], argsRestore.conflict, &conflicts))
+	}
+	if argsRestore.conflict == conflictFail && len(conflicts) > 0 {
+		return fmt.Errorf("cannot restore: resource(s) already exist: %s, use --%s skip or overwrite",
+			strings.Join(conflicts, ", "), flagConflict)
+	}
+
+	if isDryRun() {
+		for i := range plan {
+			plan[i].Status = dryRunStatus(plan[i].Status, true)
+		}
+		return printBulkResults(c, plan)
+	}
+
+	errs := make([]error, len(plan))
+	var upload []dsItem
+	var uploadIndex []int
+	for i, item := range items {
+		if plan[i].Status != statusSkipped {
+			upload = append(upload, item)
+			uploadIndex = append(uploadIndex, i)
+		}
+	}
+	uploaded, uploadErrs := uploadDsItems(c, apiURL(), upload, defaultConcurrency)
+	for j, i := range uploadIndex {
+		// the plan keeps kind and name of the items which cannot be uploaded
+		if errs[i] = uploadErrs[j]; errs[i] == nil {
+			plan[i] = uploaded[j]
+		}
+	}
+
+	for j, f := range flows {
+		i := len(items) + j
+		if plan[i].Status == statusSkipped {
+			continue
+		}
+		if plan[i], errs[i] = restoreFlow(apiURL(), f, existingFlows[f.name]); errs[i] != nil {
+			plan[i].Kind, plan[i].Name = kindFlow, f.name
+		}
+	}
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(c.OutOrStderr(), "cannot restore %s/%s\n%s\n", plan[i].Kind, plan[i].Name, err)
+			plan[i] = uploadResult{Kind: plan[i].Kind, Name: plan[i].Name, Status: statusFailed}
+			failed++
+		}
+	}
+	if err := printBulkResults(c, plan); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("cannot restore %d of %d resource(s)", failed, len(plan))
+	}
+	return nil
+}
+
+// restoreFlow creates the flow, the existing flow is replaced and restored if the flow cannot be created
+func restoreFlow(apiURL string, f resource, exists bool) (uploadResult, error) {
+	var previous map[string]interface{}
+	if exists {
+		var err error
+		if previous, err = fetchFlow(flowURL(apiURL, f.name)); err != nil {
+			return uploadResult{}, err
+		}
+	}
+	return applyFlow(apiURL, f, previous)
+}
+
+// restoreStatus plans restoring of the resource according to the conflict policy, existing resources are added to the conflicts
+func restoreStatus(kind, name, url string, exists bool, conflict string, conflicts *[]string) uploadResult {
+	status := statusCreated
+	if exists {
+		*conflicts = append(*conflicts, kind+"/"+name)
+		status = statusUpdated
+		if conflict == conflictSkip {
+			status = statusSkipped
+		}
+	}
+	return uploadResult{Kind: kind, Name: name, Location: url, Status: status}
+}
+
+// readBackup reads flows and datastore items from the backup, the backup version must be supported
+func readBackup(filename string) ([]resource, []dsItem, error) {
+	files, err := readArchive(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, ok := files[backupManifestFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("cannot restore: %s is missing in %s", backupManifestFile, filename)
+	}
+	manifest := backupManifest{}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("cannot read %s: %v", backupManifestFile, err)
+	}
+	if manifest.Version != backupVersion {
+		return nil, nil, fmt.Errorf("cannot restore: unsupported backup version %d, it must be %d", manifest.Version, backupVersion)
+	}
+
+	var flows []resource
+	for _, f := range manifest.Flows {
+		data, ok := files[path.Clean(f.File)]
+		if !ok {
+			return nil, nil, fmt.Errorf("cannot restore flow/%s: %s is missing in %s", f.Name, f.File, filename)
+		}
+		flows = append(flows, resource{kind: kindFlow, name: f.Name, filename: f.File, data: data, contentType: httputil.MediaTypeYaml})
+	}
+
+	items, err := archivedDsItems(files, manifest.Datastore, filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return flows, items, nil
+}
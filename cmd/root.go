@@ -1,11 +1,12 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"net/http"
-	"time"
 )
 
 const (
@@ -16,30 +17,119 @@ const (
 	flagContentType = "content-type"
 	flagFormat      = "format"
 	flagDslookup    = "ds-lookup"
+	flagYes         = "yes"
+	flagOutput      = "output"
+	flagOutputFile  = "output-file"
+	flagRemote      = "remote"
+	flagCheckPacks  = "check-packs"
+	flagReporter    = "reporter"
+	flagConfig      = "config"
+	flagContext     = "context"
+	flagTimeout     = "timeout"
+	flagRetries     = "retries"
+	flagVerbose     = "verbose"
+	flagPrune       = "prune"
+	flagPrefix      = "prefix"
+	flagSelector    = "selector"
+	flagDryRun      = "dry-run"
+	flagOverwrite   = "overwrite"
+	flagManifest    = "manifest"
+	flagConcurrency = "concurrency"
+	flagConflict    = "conflict"
+	flagKeyFile     = "key-file"
+
+	flagToken             = "token"
+	flagUsername          = "username"
+	flagPassword          = "password"
+	flagClientCertificate = "client-certificate"
+	flagClientKey         = "client-key"
+
+	flagCertificateAuthority  = "certificate-authority"
+	flagTLSServerName         = "tls-server-name"
+	flagInsecureSkipTLSVerify = "insecure-skip-tls-verify"
 )
 
 var client = &http.Client{
-	Timeout: time.Second * 5,
+	Timeout: defaultTimeout,
 }
 
+// stdin is used to read user's answers, it is a variable so it can be replaced in tests
+var stdin io.Reader = os.Stdin
+
 func newCmdFlyte() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "flyte",
 		Short: "Command line client for flyte",
+		PersistentPreRunE: func(c *cobra.Command, args []string) error {
+			return loadContextAndClient(c)
+		},
 	}
 
 	cmd.PersistentFlags().String(flagURL, "", "Flyte API URL. Overrides $FLYTE_API")
 	viper.BindEnv(flagURL, "FLYTE_API")
 	viper.BindPFlag(flagURL, cmd.PersistentFlags().Lookup(flagURL))
+
+	cmd.PersistentFlags().String(flagConfig, "", "Config file. Overrides $FLYTE_CONFIG (default ~/.flyte/config.yaml)")
+	viper.BindEnv(flagConfig, "FLYTE_CONFIG")
+	viper.BindPFlag(flagConfig, cmd.PersistentFlags().Lookup(flagConfig))
+
+	cmd.PersistentFlags().String(flagContext, "", "Name of the config context to use (default current context)")
+	viper.BindPFlag(flagContext, cmd.PersistentFlags().Lookup(flagContext))
+
+	cmd.PersistentFlags().StringP(flagOutput, "o", "", "Output format of the result. One of: json|yaml|name|wide (json|yaml for get, describe and test)")
+	viper.BindPFlag(flagOutput, cmd.PersistentFlags().Lookup(flagOutput))
+
+	cmd.PersistentFlags().BoolP(flagVerbose, "v", false, "Print raw flyte API responses to stderr")
+	viper.BindPFlag(flagVerbose, cmd.PersistentFlags().Lookup(flagVerbose))
+
+	cmd.PersistentFlags().Bool(flagDryRun, false, "Print requests which would change resources in flyte API instead of sending them, apply (including --prune) and restore print a plan instead")
+	viper.BindPFlag(flagDryRun, cmd.PersistentFlags().Lookup(flagDryRun))
+
+	persistentEnvFlag(cmd, flagTimeout, "FLYTE_TIMEOUT", "Time limit of a request to flyte API including retries, e.g. 30s (default 5s)")
+
+	cmd.PersistentFlags().Int(flagRetries, 0, "Number of retries of idempotent requests to flyte API failed by network errors or 429, 502, 503 and 504 responses. Overrides $FLYTE_RETRIES")
+	viper.BindEnv(flagRetries, envRetries)
+	viper.BindPFlag(flagRetries, cmd.PersistentFlags().Lookup(flagRetries))
+
+	persistentEnvFlag(cmd, flagToken, "FLYTE_TOKEN", "Bearer token for authentication to flyte API")
+	persistentEnvFlag(cmd, flagUsername, "FLYTE_USERNAME", "Username for basic authentication to flyte API")
+	persistentEnvFlag(cmd, flagPassword, "FLYTE_PASSWORD", "Password for basic authentication to flyte API")
+	persistentEnvFlag(cmd, flagClientCertificate, "FLYTE_CLIENT_CERTIFICATE", "Client certificate file for TLS authentication")
+	persistentEnvFlag(cmd, flagClientKey, "FLYTE_CLIENT_KEY", "Client key file for TLS authentication")
+	persistentEnvFlag(cmd, flagCertificateAuthority, "FLYTE_CERTIFICATE_AUTHORITY", "CA bundle file to verify flyte API certificate")
+	persistentEnvFlag(cmd, flagTLSServerName, "FLYTE_TLS_SERVER_NAME", "Server name to verify flyte API certificate against")
+	persistentEnvFlag(cmd, flagKeyFile, "FLYTE_KEY_FILE", "Key file to encrypt and decrypt datastore item files with .enc extension")
+
+	cmd.PersistentFlags().Bool(flagInsecureSkipTLSVerify, false, "Do not verify flyte API certificate, connection is insecure. Overrides $"+envInsecureSkipTLSVerify)
+	viper.BindEnv(flagInsecureSkipTLSVerify, envInsecureSkipTLSVerify)
+	viper.BindPFlag(flagInsecureSkipTLSVerify, cmd.PersistentFlags().Lookup(flagInsecureSkipTLSVerify))
+
 	cmd.AddCommand(
+		newCmdApply(),
+		newCmdBackup(),
+		newCmdConfig(),
+		newCmdDelete(),
+		newCmdDescribe(),
+		newCmdDiff(),
+		newCmdDs(),
+		newCmdGet(),
+		newCmdRestore(),
 		newCmdTest(),
 		newCmdUpload(),
+		newCmdValidate(),
 		newCmdVersion(),
 	)
 
 	return cmd
 }
 
+// persistentEnvFlag adds a global string option which can be also set by the environment variable
+func persistentEnvFlag(cmd *cobra.Command, name, env, usage string) {
+	cmd.PersistentFlags().String(name, "", fmt.Sprintf("%s. Overrides $%s", usage, env))
+	viper.BindEnv(name, env)
+	viper.BindPFlag(name, cmd.PersistentFlags().Lookup(name))
+}
+
 func Execute() {
 	if err := newCmdFlyte().Execute(); err != nil {
 		os.Exit(1)
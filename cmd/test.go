@@ -11,13 +11,14 @@ import (
 	"github.com/ghodss/yaml"
 	"strings"
 	"github.com/HotelsDotCom/flyte/httputil"
-	"github.com/spf13/viper"
+	"time"
 )
 
 var argsTest = struct {
 	filename string
 	dsLookup bool
 	format   string
+	reporter string
 }{}
 
 func newCmdTest() *cobra.Command {
@@ -28,11 +29,12 @@ func newCmdTest() *cobra.Command {
 		RunE:  runTest,
 	}
 
-	cmd.Flags().StringVarP(&argsTest.filename, flagFilename, "f", "", "filename of the file with step and test data")
+	cmd.Flags().StringVarP(&argsTest.filename, flagFilename, "f", "", "filename of the file with step and test data, a directory or a glob pattern of such files")
 	cmd.MarkFlagRequired(flagFilename)
 
 	cmd.Flags().BoolVar(&argsTest.dsLookup, flagDslookup, true, "lookup datastore item in the flyte API unless present in test data")
 	cmd.Flags().StringVar(&argsTest.format, flagFormat, "json", "Output format. One of: json|yaml")
+	cmd.Flags().StringVar(&argsTest.reporter, flagReporter, "", "Report results in machine readable format instead of printing the action. One of: junit|tap|json")
 	return cmd
 }
 
@@ -41,6 +43,17 @@ Executes the step in the provided file. Test files MUST contain the
 step, and trigger event definitions, and can optionally contain context and datastore
 items as required.
 
+Test data can optionally contain expected action. Only the fields present in the
+expectation, down to the keys of input and context, are compared with the resulting
+action, other fields of the action are ignored. Differences are printed and
+the test fails. Use 'noAction: true' to expect that the step does not produce any action:
+testData:
+  expect:
+    name: SendMessage
+    packName: Slack
+    input:
+      message: 'Hello'
+
 Examples:
   # Test a step from my_step.yaml file
   flyte test -f ./my_step.yaml
@@ -82,37 +95,133 @@ testData
       name: Slack
     name: ReceivedMessage
 EOF
+
+You can run all test files (*.json, *.yaml, *.yml) from a directory and its subdirectories
+or all files matching a glob pattern. Each file is reported as passed or failed followed
+by a summary, the command fails if any of the tests fails:
+  flyte test -f ./tests/
+  flyte test -f './tests/*.yaml'
+
+Test file can contain a whole flow instead of a single step, and a sequence of events
+instead of a single event. Every event triggers steps without dependsOn and the steps
+depending on any step which fired for one of the earlier events. Context produced by the
+steps is passed to the next ones. Expected actions can be set per step id,
+the last action of the step is compared with the expectation:
+---
+flow:
+  name: status-flow
+  steps:
+  - id: status
+    ...
+  - id: confirm
+    dependsOn: [status]
+    ...
+testData:
+  events:
+  - pack:
+      name: Slack
+    name: ReceivedMessage
+  - pack:
+      name: Slack
+    name: MessageSent
+  expectSteps:
+    confirm:
+      name: SendMessage
+
+Use --reporter option to report results for CI tools. Every test file is reported as
+one test case with its duration, failure message and the resulting action:
+  flyte test -f ./tests/ --reporter junit > flyte-tests.xml
 `
 
 func runTest(c *cobra.Command, args []string) error {
-	var step testStep
-	if err := unmarshalFile(argsTest.filename, &step); err != nil {
+	report, err := testReporter(argsTest.reporter)
+	if err != nil {
+		return err
+	}
+
+	files, suite, err := testFiles(argsTest.filename)
+	if err != nil {
 		return err
 	}
+	if report != nil {
+		return runTestReport(c, files, report)
+	}
+	if suite {
+		return runTestSuite(c, files)
+	}
 
-	action, err := step.execute(argsTest.dsLookup, viper.GetString(flagURL))
+	format, err := outputFormat(c)
 	if err != nil {
 		return err
 	}
+	result := runTestFile(argsTest.filename)
+	if _, ok := result.err.(expectationError); result.err != nil && !ok {
+		return result.err
+	}
 
-	out, err := marshal(action, argsTest.format)
+	out, err := marshal(result.output(), format)
 	if err != nil {
 		return err
 	}
 
-	_, err = fmt.Fprintln(c.OutOrStdout(), string(out))
-	return err
+	if _, err = fmt.Fprintln(c.OutOrStdout(), string(out)); err != nil {
+		return err
+	}
+	return result.err
 }
 
+// runTestFile executes the step from the file and checks the action against the expectation if present
+func runTestFile(filename string) (result testResult) {
+	result.filename = filename
+	start := time.Now()
+	defer func() {
+		result.duration = time.Since(start)
+	}()
+
+	var step testStep
+	if result.err = unmarshalFile(filename, &step); result.err != nil {
+		return result
+	}
+
+	if step.Flow != nil {
+		result.name = step.Flow.Name
+		result.flow, result.err = step.executeFlow(argsTest.dsLookup, apiURL())
+		if result.err == nil {
+			if diffs := step.TestData.ExpectSteps.diff(result.flow); len(diffs) > 0 {
+				result.err = expectationError{diffs: diffs}
+			}
+		}
+		return result
+	}
+
+	result.name = step.Step.ID
+	result.action, result.err = step.execute(argsTest.dsLookup, apiURL())
+	if result.err != nil {
+		return result
+	}
+
+	if step.TestData.Expect != nil {
+		if diffs := step.TestData.Expect.diff(result.action); len(diffs) > 0 {
+			result.err = expectationError{diffs: diffs}
+		}
+	}
+	return result
+}
+
+// testStep is a test of a single step or of a whole flow when the flow is present
 type testStep struct {
 	Step     execution.Step
+	Flow     *flowDef
 	TestData testData
 }
 
 type testData struct {
-	Event     event
-	Context   map[string]string
-	Datastore map[string]interface{}
+	Event       event
+	Events      []event
+	Context     map[string]string
+	Datastore   map[string]interface{}
+	Expect      *testExpect
+	ExpectSteps stepExpectations
 }
 
 // not sure why execution.Event replaces name with json tag event
@@ -127,13 +236,17 @@ func (t testStep) execute(dsLookup bool, apiURL string) (*testAction, error) {
 	//override flyte's default datastore function
 	template.AddStaticContextEntry("datastore", datastoreFn(t.TestData.Datastore, dsLookup, apiURL))
 
+	return executeStep(t.Step, t.TestData.Event, t.TestData.Context)
+}
+
+func executeStep(step execution.Step, event event, context map[string]string) (*testAction, error) {
 	e := execution.Event{
-		Pack:    t.TestData.Event.Pack,
-		Name:    t.TestData.Event.Name,
-		Payload: t.TestData.Event.Payload,
+		Pack:    event.Pack,
+		Name:    event.Name,
+		Payload: event.Payload,
 	}
 
-	action, err := t.Step.Execute(e, t.TestData.Context)
+	action, err := step.Execute(e, context)
 	if err != nil {
 		return nil, err
 	}
@@ -210,22 +323,36 @@ func datastoreFn(datastore map[string]interface{}, dsLookup bool, apiURL string)
 
 func findDatastoreItem(url string) (interface{}, error) {
 
-	resp, err := client.Get(url)
+	b, contentType, err := getDatastoreValue(url)
 	if err != nil {
+		if e, ok := err.(responseError); ok {
+			return nil, fmt.Errorf("invalid http response %d %s", e.statusCode, e.status)
+		}
 		return nil, err
 	}
+
+	return unmarshalValue(b, contentType)
+}
+
+// getDatastoreValue returns raw value of the datastore item and its content type
+func getDatastoreValue(url string) ([]byte, string, error) {
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, "", err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("invalid http response %d %s", resp.StatusCode, resp.Status)
+		return nil, "", newResponseError(resp)
 	}
 
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return unmarshalValue(b, resp.Header.Get(httputil.HeaderContentType))
+	return b, resp.Header.Get(httputil.HeaderContentType), nil
 }
 
 // parse data into the expected struct
@@ -40,6 +40,13 @@ func TestTestCommand_ShouldExecuteStepAndReturnOutputAsYaml(t *testing.T) {
 	assert.Equal(t, yamlOutput, output)
 }
 
+func TestTestCommand_ShouldExecuteStepAndReturnOutputAsYamlWithOutputOption(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/step-test.json", "-o", "yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, yamlOutput, output)
+}
+
 func TestTestCommand_ShouldLookupDataItemInTheFlyteAPI(t *testing.T) {
 	rec := requestRec{}
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	jsont "github.com/HotelsDotCom/flyte/json"
+)
+
+// testExpect is an expected action of the step,
+// only the fields which are set are compared with the actual action
+type testExpect struct {
+	NoAction   bool              `json:"noAction,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	PackName   string            `json:"packName,omitempty"`
+	PackLabels map[string]string `json:"packLabels,omitempty"`
+	Input      jsont.Json        `json:"input,omitempty"`
+	Context    map[string]string `json:"context,omitempty"`
+}
+
+type expectationError struct {
+	diffs []string
+}
+
+func (e expectationError) Error() string {
+	return fmt.Sprintf("action does not match expectation:\n  %s", strings.Join(e.diffs, "\n  "))
+}
+
+// diff returns differences between the expectation and the action, one per line
+func (e testExpect) diff(action *testAction) []string {
+	if e.NoAction {
+		if action == nil {
+			return nil
+		}
+		return []string{fmt.Sprintf("expected no action, got %s action", action.Name)}
+	}
+	if action == nil {
+		return []string{"expected action, got no action"}
+	}
+
+	var diffs []string
+	if e.Name != "" && e.Name != action.Name {
+		diffs = append(diffs, fmt.Sprintf("name: expected %q, got %q", e.Name, action.Name))
+	}
+	if e.PackName != "" && e.PackName != action.PackName {
+		diffs = append(diffs, fmt.Sprintf("packName: expected %q, got %q", e.PackName, action.PackName))
+	}
+	if e.PackLabels != nil {
+		diffs = diffValues(diffs, "packLabels", normalize(e.PackLabels), normalize(action.PackLabels))
+	}
+	if e.Input != nil {
+		diffs = diffValues(diffs, "input", normalize(e.Input), normalize(action.Input))
+	}
+	if e.Context != nil {
+		diffs = diffValues(diffs, "context", normalize(e.Context), normalize(action.Context))
+	}
+	return diffs
+}
+
+// normalize converts the value to the generic representation of its JSON, so values can be compared
+func normalize(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	var n interface{}
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return n
+}
+
+// diffValues appends differences between normalized values to diffs, objects are compared key by key.
+// Only keys present in the expected object are compared, other keys of the actual object are ignored.
+func diffValues(diffs []string, path string, want, got interface{}) []string {
+	wantMap, wok := want.(map[string]interface{})
+	gotMap, gok := got.(map[string]interface{})
+	if !wok || !gok {
+		if !reflect.DeepEqual(want, got) {
+			diffs = append(diffs, fmt.Sprintf("%s: expected %s, got %s", path, formatValue(want), formatValue(got)))
+		}
+		return diffs
+	}
+
+	keys := make([]string, 0, len(wantMap))
+	for k := range wantMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		w := wantMap[k]
+		g, ok := gotMap[k]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s.%s: expected %s, got nothing", path, k, formatValue(w)))
+			continue
+		}
+		diffs = diffValues(diffs, path+"."+k, w, g)
+	}
+	return diffs
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "nothing"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// stepExpectations are expected actions of flow steps by step ids
+type stepExpectations map[string]testExpect
+
+// diff compares the last action of every step with its expectation
+func (e stepExpectations) diff(result *flowTestResult) []string {
+	last := map[string]*testAction{}
+	for _, f := range result.Fired {
+		last[f.Step] = f.Action
+	}
+
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var diffs []string
+	for _, id := range ids {
+		for _, d := range e[id].diff(last[id]) {
+			diffs = append(diffs, fmt.Sprintf("step %s: %s", id, d))
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCommand_ShouldPassWhenActionMatchesExpectation(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/step-expect.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonOutput, output)
+}
+
+func TestTestCommand_ShouldPassWhenNoActionIsExpected(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/step-expect-no-action.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "null\n", output)
+}
+
+func TestTestCommand_ShouldPrintActionAndFailWhenActionDoesNotMatchExpectation(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/step-expect-mismatch.yaml")
+
+	require.Error(t, err)
+	assert.Contains(t, output, jsonOutput)
+	assert.Equal(t, `action does not match expectation:
+  name: expected "SendReply", got "SendMessage"
+  input.channelId: expected "456", got "123"
+  input.threadId: expected "1", got nothing`, err.Error())
+}
+
+func TestTestExpect_ShouldIgnoreFieldsWhichAreNotInExpectation(t *testing.T) {
+	expect := testExpect{
+		Input:   map[string]interface{}{"channelId": "123", "blocks": map[string]interface{}{"title": "status"}},
+		Context: map[string]string{"ChannelID": "123"},
+	}
+	action := &testAction{
+		Name:    "SendMessage",
+		Input:   map[string]interface{}{"channelId": "123", "message": "hello", "blocks": map[string]interface{}{"title": "status", "footer": "flyte"}},
+		Context: map[string]string{"ChannelID": "123", "UserID": "johnny"},
+	}
+
+	assert.Empty(t, expect.diff(action))
+}
+
+func TestTestExpect_ShouldReportUnexpectedAction(t *testing.T) {
+	diffs := testExpect{NoAction: true}.diff(&testAction{Name: "SendMessage"})
+
+	assert.Equal(t, []string{"expected no action, got SendMessage action"}, diffs)
+}
+
+func TestTestExpect_ShouldReportMissingAction(t *testing.T) {
+	diffs := testExpect{Name: "SendMessage"}.diff(nil)
+
+	assert.Equal(t, []string{"expected action, got no action"}, diffs)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/HotelsDotCom/flyte/execution"
+	"github.com/HotelsDotCom/flyte/template"
+)
+
+// flowTestResult lists steps fired by the events in the order of firing and steps which have not fired
+type flowTestResult struct {
+	Fired    []firedStep `json:"fired"`
+	NotFired []string    `json:"notFired,omitempty"`
+}
+
+type firedStep struct {
+	Event  string      `json:"event"`
+	Step   string      `json:"step"`
+	Action *testAction `json:"action"`
+}
+
+// executeFlow sends the events one by one to the flow steps in dependency order.
+// Every event triggers steps without dependsOn and the steps depending on any step fired
+// by one of the earlier events, as the flyte engine does. Context produced by the fired steps
+// is passed to the steps triggered by the next event.
+func (t testStep) executeFlow(dsLookup bool, apiURL string) (*flowTestResult, error) {
+	//override flyte's default datastore function
+	template.AddStaticContextEntry("datastore", datastoreFn(t.TestData.Datastore, dsLookup, apiURL))
+
+	steps, err := sortSteps(t.Flow.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	events := t.TestData.Events
+	if len(events) == 0 && t.TestData.Event.Name != "" {
+		events = []event{t.TestData.Event}
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("cannot test flow %s: test data must contain at least one event", t.Flow.Name)
+	}
+
+	result := &flowTestResult{Fired: []firedStep{}}
+	context := copyContext(t.TestData.Context)
+	fired := map[string]bool{}
+	for i, e := range events {
+		current := map[string]bool{}
+		next := copyContext(context)
+		for _, s := range steps {
+			if !triggeredBy(s, fired) {
+				continue
+			}
+
+			action, err := executeStep(s, e, copyContext(context))
+			if err != nil {
+				return nil, fmt.Errorf("cannot execute step %s with event[%d] %s: %v", s.ID, i, e.Name, err)
+			}
+			if action == nil {
+				continue
+			}
+
+			current[s.ID] = true
+			for k, v := range action.Context {
+				next[k] = v
+			}
+			result.Fired = append(result.Fired, firedStep{
+				Event:  fmt.Sprintf("%s.%s", e.Pack.Name, e.Name),
+				Step:   s.ID,
+				Action: action,
+			})
+		}
+		context = next
+		for id := range current {
+			fired[id] = true
+		}
+	}
+
+	for _, s := range steps {
+		if !fired[s.ID] {
+			result.NotFired = append(result.NotFired, s.ID)
+		}
+	}
+	return result, nil
+}
+
+// triggeredBy returns true if the step can be triggered after the steps fired by the earlier events
+func triggeredBy(s execution.Step, fired map[string]bool) bool {
+	if len(s.DependsOn) == 0 {
+		return true
+	}
+	for _, d := range s.DependsOn {
+		if fired[d] {
+			return true
+		}
+	}
+	return false
+}
+
+// sortSteps orders steps so every step comes after the steps it depends on,
+// otherwise the order of steps in the flow is kept
+func sortSteps(steps []execution.Step) ([]execution.Step, error) {
+	ids := map[string]bool{}
+	for _, s := range steps {
+		ids[s.ID] = true
+	}
+
+	sorted := make([]execution.Step, 0, len(steps))
+	done := map[string]bool{}
+	for len(sorted) < len(steps) {
+		progress := false
+		for _, s := range steps {
+			if done[s.ID] || !dependenciesDone(s, ids, done) {
+				continue
+			}
+			done[s.ID] = true
+			sorted = append(sorted, s)
+			progress = true
+		}
+		if !progress {
+			return nil, fmt.Errorf("cannot sort flow steps: dependsOn contains a cycle")
+		}
+	}
+	return sorted, nil
+}
+
+// dependenciesDone ignores dependencies on unknown steps, those are reported by flow validation
+func dependenciesDone(s execution.Step, ids, done map[string]bool) bool {
+	for _, d := range s.DependsOn {
+		if ids[d] && !done[d] {
+			return false
+		}
+	}
+	return true
+}
+
+func copyContext(context map[string]string) map[string]string {
+	c := make(map[string]string, len(context))
+	for k, v := range context {
+		c[k] = v
+	}
+	return c
+}
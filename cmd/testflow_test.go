@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCommand_ShouldExecuteFlowStepsInDependencyOrderThreadingContext(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/flow-test.yaml", "-o", "yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, flowYamlOutput, output)
+}
+
+func TestTestCommand_ShouldFailWhenFlowStepDoesNotMatchExpectation(t *testing.T) {
+	ts := newFlowTest(t)
+	ts.TestData.ExpectSteps["status"] = testExpect{NoAction: true}
+
+	result, err := ts.executeFlow(false, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"step status: expected no action, got SendMessage action"}, ts.TestData.ExpectSteps.diff(result))
+}
+
+func TestTestCommand_ShouldNotFireDependentStepWithoutItsDependency(t *testing.T) {
+	ts := newFlowTest(t)
+	ts.TestData.Events = ts.TestData.Events[1:]
+
+	result, err := ts.executeFlow(false, "")
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Fired)
+	assert.Equal(t, []string{"status", "confirm", "never"}, result.NotFired)
+}
+
+func TestTestCommand_ShouldFireDependentStepAfterUnrelatedEvent(t *testing.T) {
+	ts := newFlowTest(t)
+	unrelated := event{Pack: execution.Pack{Name: "Slack"}, Name: "UserJoined"}
+	ts.TestData.Events = []event{ts.TestData.Events[0], unrelated, ts.TestData.Events[1]}
+
+	result, err := ts.executeFlow(false, "")
+	require.NoError(t, err)
+
+	require.Len(t, result.Fired, 2)
+	assert.Equal(t, "status", result.Fired[0].Step)
+	assert.Equal(t, "confirm", result.Fired[1].Step)
+	assert.Equal(t, "Slack.MessageSent", result.Fired[1].Event)
+	assert.Equal(t, []string{"never"}, result.NotFired)
+}
+
+func TestSortSteps_ShouldFailForDependsOnCycle(t *testing.T) {
+	steps := []execution.Step{
+		{ID: "a", DependsOn: []string{"b"}},
+		{ID: "b", DependsOn: []string{"a"}},
+	}
+
+	_, err := sortSteps(steps)
+
+	require.Error(t, err)
+	assert.Equal(t, "cannot sort flow steps: dependsOn contains a cycle", err.Error())
+}
+
+func newFlowTest(t *testing.T) testStep {
+	var ts testStep
+	require.NoError(t, unmarshalFile("testdata/flow-test.yaml", &ts))
+	return ts
+}
+
+const flowYamlOutput = `fired:
+- action:
+    context:
+      ChannelID: "123"
+      UserID: johnny
+    input:
+      channelId: "123"
+      message: 'Hey <@johnny>, I''m up and running :run:'
+    name: SendMessage
+    packName: Slack
+  event: Slack.ReceivedMessage
+  step: status
+- action:
+    context:
+      ChannelID: "123"
+      Confirmed: "yes"
+      UserID: johnny
+    input:
+      channelId: "123"
+      message: Status sent to <@johnny>
+    name: SendMessage
+    packName: Slack
+  event: Slack.MessageSent
+  step: confirm
+notFired:
+- never
+
+`
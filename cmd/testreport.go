@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+// testReport writes test results to w in a machine readable format
+type testReport func(w io.Writer, results []testResult) error
+
+func testReporter(name string) (testReport, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "junit":
+		return reportJUnit, nil
+	case "tap":
+		return reportTAP, nil
+	case "json":
+		return reportJSON, nil
+	default:
+		return nil, fmt.Errorf("unsupported reporter %q, it must be one of: junit|tap|json", name)
+	}
+}
+
+// runTestReport runs every test file and reports results, it fails if any of the tests fails
+func runTestReport(c *cobra.Command, files []string, report testReport) error {
+	results := runTestFiles(files)
+	if err := report(c.OutOrStdout(), results); err != nil {
+		return err
+	}
+	return suiteError(results)
+}
+
+// caseName is a step id or file name if step could not be read
+func (r testResult) caseName() string {
+	if r.name != "" {
+		return r.name
+	}
+	return filepath.Base(r.filename)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func reportJUnit(w io.Writer, results []testResult) error {
+	suite := junitTestSuite{Name: "flyte", Tests: len(results), Failures: countFailed(results)}
+	total := 0.0
+	for _, r := range results {
+		tc := junitTestCase{
+			Name:      r.caseName(),
+			Classname: r.filename,
+			Time:      fmt.Sprintf("%.3f", r.duration.Seconds()),
+		}
+		if r.output() != nil {
+			out, err := marshal(r.output(), "json")
+			if err != nil {
+				return err
+			}
+			tc.SystemOut = string(out)
+		}
+		if !r.passed() {
+			tc.Failure = &junitFailure{Message: strings.SplitN(r.err.Error(), "\n", 2)[0], Text: r.err.Error()}
+		}
+		total += r.duration.Seconds()
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+
+	out, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, out)
+	return err
+}
+
+type tapDiagnostic struct {
+	DurationMs float64     `json:"duration_ms"`
+	Message    string      `json:"message,omitempty"`
+	Action     interface{} `json:"action,omitempty"`
+}
+
+func reportTAP(w io.Writer, results []testResult) error {
+	fmt.Fprintf(w, "TAP version 13\n1..%d\n", len(results))
+	for i, r := range results {
+		status := "ok"
+		d := tapDiagnostic{DurationMs: float64(r.duration.Nanoseconds()) / 1e6, Action: r.output()}
+		if !r.passed() {
+			status = "not ok"
+			d.Message = r.err.Error()
+		}
+
+		block, err := yaml.Marshal(d)
+		if err != nil {
+			return err
+		}
+		indented := strings.Replace(strings.TrimSuffix(string(block), "\n"), "\n", "\n  ", -1)
+		fmt.Fprintf(w, "%s %d - %s (%s)\n  ---\n  %s\n  ...\n", status, i+1, r.caseName(), r.filename, indented)
+	}
+	return nil
+}
+
+type jsonTestResult struct {
+	File     string      `json:"file"`
+	Name     string      `json:"name"`
+	Passed   bool        `json:"passed"`
+	Duration float64     `json:"duration"`
+	Error    string      `json:"error,omitempty"`
+	Action   interface{} `json:"action,omitempty"`
+}
+
+func reportJSON(w io.Writer, results []testResult) error {
+	report := make([]jsonTestResult, len(results))
+	for i, r := range results {
+		report[i] = jsonTestResult{
+			File:     r.filename,
+			Name:     r.caseName(),
+			Passed:   r.passed(),
+			Duration: r.duration.Seconds(),
+			Action:   r.output(),
+		}
+		if !r.passed() {
+			report[i].Error = r.err.Error()
+		}
+	}
+
+	out, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
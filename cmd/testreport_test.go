@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCommand_ShouldReportResultsAsJUnit(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/suite", "--ds-lookup=false", "--reporter", "junit")
+	require.Error(t, err)
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal([]byte(output), &report))
+	require.Len(t, report.Suites, 1)
+
+	suite := report.Suites[0]
+	assert.Equal(t, 3, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	require.Len(t, suite.Cases, 3)
+
+	failed := suite.Cases[0]
+	assert.Equal(t, "status", failed.Name)
+	assert.Equal(t, "testdata/suite/missing-ds.yml", failed.Classname)
+	require.NotNil(t, failed.Failure)
+	assert.Contains(t, failed.Failure.Message, "cannot find datastore item key=missing")
+
+	passed := suite.Cases[2]
+	assert.Equal(t, "testdata/suite/status.yaml", passed.Classname)
+	assert.Nil(t, passed.Failure)
+	assert.Regexp(t, `^\d+\.\d{3}$`, passed.Time)
+	assert.Equal(t, jsonOutput, passed.SystemOut+"\n")
+}
+
+func TestTestCommand_ShouldReportResultsAsTAP(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/suite", "--ds-lookup=false", "--reporter", "tap")
+	require.Error(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(`^TAP version 13\n1\.\.3\n`), output)
+	assert.Contains(t, output, "not ok 1 - status (testdata/suite/missing-ds.yml)\n  ---\n  duration_ms: ")
+	assert.Contains(t, output, "  message: cannot find datastore item key=missing")
+	assert.Contains(t, output, "ok 3 - status (testdata/suite/status.yaml)\n  ---\n  action:\n    context:\n      ChannelID: \"123\"")
+}
+
+func TestTestCommand_ShouldReportSingleTestAsJson(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/step-test.yaml", "--reporter", "json")
+	require.NoError(t, err)
+
+	var report []jsonTestResult
+	require.NoError(t, json.Unmarshal([]byte(output), &report))
+	require.Len(t, report, 1)
+
+	assert.Equal(t, "testdata/step-test.yaml", report[0].File)
+	assert.Equal(t, "status", report[0].Name)
+	assert.True(t, report[0].Passed)
+	assert.Equal(t, "SendMessage", report[0].Action.(map[string]interface{})["name"])
+}
+
+func TestTestCommand_ShouldFailForUnsupportedReporter(t *testing.T) {
+	_, err := executeCommand("test", "-f", "testdata/step-test.yaml", "--reporter", "xunit")
+
+	require.Error(t, err)
+	assert.Equal(t, `unsupported reporter "xunit", it must be one of: junit|tap|json`, err.Error())
+}
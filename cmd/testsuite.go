@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// testFiles returns test files for the filename which could be a file, a directory or a glob pattern.
+// It also returns true when the files should be run as a test suite rather than a single test.
+func testFiles(filename string) ([]string, bool, error) {
+	if filename == "-" {
+		return []string{filename}, false, nil
+	}
+
+	if strings.ContainsAny(filename, "*?[") {
+		files, err := filepath.Glob(filename)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(files) == 0 {
+			return nil, false, fmt.Errorf("cannot find test files matching %s", filename)
+		}
+		sort.Strings(files)
+		return files, true, nil
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, false, err
+	}
+	if !info.IsDir() {
+		return []string{filename}, false, nil
+	}
+
+	var files []string
+	err = filepath.Walk(filename, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch filepath.Ext(path) {
+		case ".json", ".yaml", ".yml":
+			if !info.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if len(files) == 0 {
+		return nil, false, fmt.Errorf("cannot find test files in %s", filename)
+	}
+	return files, true, nil
+}
+
+type testResult struct {
+	filename string
+	name     string
+	action   *testAction
+	flow     *flowTestResult
+	err      error
+	duration time.Duration
+}
+
+func (r testResult) passed() bool {
+	return r.err == nil
+}
+
+// output is the resulting action of a step test or the result of a flow test
+func (r testResult) output() interface{} {
+	if r.flow != nil {
+		return r.flow
+	}
+	if r.action != nil {
+		return r.action
+	}
+	return nil
+}
+
+func runTestFiles(files []string) []testResult {
+	results := make([]testResult, len(files))
+	for i, f := range files {
+		results[i] = runTestFile(f)
+	}
+	return results
+}
+
+// runTestSuite runs every test file and prints a summary, it fails if any of the tests fails
+func runTestSuite(c *cobra.Command, files []string) error {
+	results := runTestFiles(files)
+
+	out := c.OutOrStdout()
+	for _, r := range results {
+		if r.passed() {
+			fmt.Fprintf(out, "PASS  %s\n", r.filename)
+			continue
+		}
+		fmt.Fprintf(out, "FAIL  %s\n      %s\n", r.filename, strings.Replace(r.err.Error(), "\n", "\n      ", -1))
+	}
+
+	failed := countFailed(results)
+	fmt.Fprintf(out, "\n%d passed, %d failed\n", len(results)-failed, failed)
+	return suiteError(results)
+}
+
+func countFailed(results []testResult) int {
+	failed := 0
+	for _, r := range results {
+		if !r.passed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+func suiteError(results []testResult) error {
+	if failed := countFailed(results); failed > 0 {
+		return fmt.Errorf("%d of %d test(s) failed", failed, len(results))
+	}
+	return nil
+}
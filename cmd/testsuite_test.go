@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCommand_ShouldRunAllTestFilesFromDirectory(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/suite", "--ds-lookup=false")
+
+	require.Error(t, err)
+	assert.Equal(t, "1 of 3 test(s) failed", err.Error())
+	assert.Contains(t, output, "FAIL  testdata/suite/missing-ds.yml\n      ")
+	assert.Contains(t, output, "cannot find datastore item key=missing")
+	assert.Contains(t, output, "PASS  testdata/suite/nested/status.json\nPASS  testdata/suite/status.yaml\n\n2 passed, 1 failed\n")
+	assert.NotContains(t, output, "README.txt")
+}
+
+func TestTestCommand_ShouldRunAllTestFilesMatchingGlob(t *testing.T) {
+	output, err := executeCommand("test", "-f", "testdata/suite/*.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "PASS  testdata/suite/status.yaml\n\n1 passed, 0 failed\n", output)
+}
+
+func TestTestCommand_ShouldFailWhenNoFilesMatchGlob(t *testing.T) {
+	_, err := executeCommand("test", "-f", "testdata/suite/*.none")
+
+	require.Error(t, err)
+	assert.Equal(t, "cannot find test files matching testdata/suite/*.none", err.Error())
+}
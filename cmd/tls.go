@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const envInsecureSkipTLSVerify = "FLYTE_INSECURE_SKIP_TLS_VERIFY"
+
+// tlsSettings control how the server certificate of flyte API is verified
+type tlsSettings struct {
+	CertificateAuthority  string
+	TLSServerName         string
+	InsecureSkipTLSVerify bool
+}
+
+// resolveTLS takes TLS settings from the options or environment variables and then from the active context.
+// Certificate authority from the options replaces the context's insecure mode, insecure-skip-tls-verify from
+// the options replaces the context's one and when it is true also the context's certificate authority.
+func resolveTLS(c *cobra.Command) tlsSettings {
+	settings := tlsSettings{
+		CertificateAuthority:  activeContext.CertificateAuthority,
+		TLSServerName:         activeContext.TLSServerName,
+		InsecureSkipTLSVerify: activeContext.InsecureSkipTLSVerify,
+	}
+
+	if viper.GetString(flagCertificateAuthority) != "" {
+		settings.InsecureSkipTLSVerify = false
+	}
+	if _, env := os.LookupEnv(envInsecureSkipTLSVerify); env || c.Flags().Changed(flagInsecureSkipTLSVerify) {
+		settings.InsecureSkipTLSVerify = viper.GetBool(flagInsecureSkipTLSVerify)
+		if settings.InsecureSkipTLSVerify {
+			settings.CertificateAuthority = ""
+		}
+	}
+	override(&settings.CertificateAuthority, flagCertificateAuthority)
+	override(&settings.TLSServerName, flagTLSServerName)
+	return settings
+}
+
+// newTLSConfig creates TLS config trusting the CA bundle in addition to system roots
+func newTLSConfig(settings tlsSettings, auth authConfig) (*tls.Config, error) {
+	if settings.InsecureSkipTLSVerify && settings.CertificateAuthority != "" {
+		return nil, errors.New("certificate authority cannot be used with insecure-skip-tls-verify, choose one of them")
+	}
+
+	config := &tls.Config{
+		ServerName:         settings.TLSServerName,
+		InsecureSkipVerify: settings.InsecureSkipTLSVerify,
+	}
+
+	if settings.CertificateAuthority != "" {
+		pem, err := ioutil.ReadFile(settings.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load certificate authority: %v", err)
+		}
+
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("cannot load certificate authority: no certificates found in %s", settings.CertificateAuthority)
+		}
+		config.RootCAs = roots
+	}
+
+	if auth.ClientCertificate != "" {
+		cert, err := tls.LoadX509KeyPair(auth.ClientCertificate, auth.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// tlsTransport explains failed verification of the server certificate
+type tlsTransport struct {
+	base *http.Transport
+}
+
+func (t *tlsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, verificationError(err, req.URL.Host)
+	}
+	return resp, nil
+}
+
+// verificationError replaces certificate verification errors with a hint how to fix them
+func verificationError(err error, host string) error {
+	switch cause := certificateCause(err).(type) {
+	case x509.UnknownAuthorityError:
+		return certificateError{cause: err, msg: fmt.Sprintf("cannot verify certificate of %s: signed by unknown authority, "+
+			"use --certificate-authority to trust the CA bundle or --insecure-skip-tls-verify to skip verification", host)}
+	case x509.HostnameError:
+		return certificateError{cause: err, msg: fmt.Sprintf("cannot verify certificate of %s: %v, "+
+			"use --tls-server-name to verify against other name", host, cause)}
+	case x509.CertificateInvalidError:
+		return certificateError{cause: err, msg: fmt.Sprintf("cannot verify certificate of %s: %v", host, cause)}
+	}
+	return err
+}
+
+// certificateError is the verification error with a hint, it keeps the cause so it is not retried
+type certificateError struct {
+	cause error
+	msg   string
+}
+
+func (e certificateError) Error() string {
+	return e.msg
+}
+
+func (e certificateError) Cause() error {
+	return e.cause
+}
+
+// certificateCause finds the certificate verification error which caused the err, nil if there is none
+func certificateCause(err error) error {
+	for e := err; e != nil; e = unwrap(e) {
+		switch e.(type) {
+		case x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError:
+			return e
+		}
+	}
+	return nil
+}
+
+func unwrap(err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	case interface{ Cause() error }:
+		return e.Cause()
+	}
+	return nil
+}
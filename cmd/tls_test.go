@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLS_ShouldFailForUnknownAuthority(t *testing.T) {
+	//given
+	ts := newTLSServer(t, false)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot verify certificate of "+ts.Listener.Addr().String()+": signed by unknown authority, "+
+		"use --certificate-authority to trust the CA bundle or --insecure-skip-tls-verify to skip verification")
+}
+
+func TestTLS_ShouldTrustCertificateAuthority(t *testing.T) {
+	//given
+	ts := newTLSServer(t, false)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("get", "flows", "--url", ts.URL, "--certificate-authority", "./testdata/tls/ca.crt")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "NAME     DESCRIPTION\nmy-flow  \n", output)
+}
+
+func TestTLS_ShouldSkipVerificationWhenInsecure(t *testing.T) {
+	//given
+	ts := newTLSServer(t, false)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL, "--insecure-skip-tls-verify")
+
+	//then
+	require.NoError(t, err)
+}
+
+func TestTLS_ShouldVerifyAgainstServerName(t *testing.T) {
+	//given
+	ts := newTLSServer(t, false)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL, "--certificate-authority", "./testdata/tls/ca.crt", "--tls-server-name", "flyte.test")
+
+	//then
+	require.NoError(t, err)
+}
+
+func TestTLS_ShouldFailForWrongServerName(t *testing.T) {
+	//given
+	ts := newTLSServer(t, false)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("get", "flows", "--url", ts.URL, "--certificate-authority", "./testdata/tls/ca.crt", "--tls-server-name", "other.test")
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use --tls-server-name to verify against other name")
+}
+
+func TestTLS_ShouldUseSettingsFromContext(t *testing.T) {
+	//given
+	ts := newTLSServer(t, true)
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n"+
+		"  certificate-authority: ./testdata/tls/ca.crt\n  auth:\n    client-certificate: ./testdata/tls/client.crt\n"+
+		"    client-key: ./testdata/tls/client.key\n", ts.URL))
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("get", "flows", "--config", config)
+
+	//then
+	require.NoError(t, err)
+}
+
+func TestTLS_ShouldFailForCertificateAuthorityWithInsecure(t *testing.T) {
+	_, err := executeCommand("get", "flows", "--url", "https://localhost:1", "--certificate-authority", "./testdata/tls/ca.crt", "--insecure-skip-tls-verify")
+
+	require.Error(t, err)
+	assert.Equal(t, "certificate authority cannot be used with insecure-skip-tls-verify, choose one of them", err.Error())
+}
+
+func TestTLS_ShouldFailForInvalidCertificateAuthority(t *testing.T) {
+	_, err := executeCommand("get", "flows", "--url", "https://localhost:1", "--certificate-authority", "./testdata/tls/client.key")
+
+	require.Error(t, err)
+	assert.Equal(t, "cannot load certificate authority: no certificates found in ./testdata/tls/client.key", err.Error())
+}
+
+// newTLSServer starts flyte API with certificate signed by the test CA, optionally requiring client certificate
+func newTLSServer(t *testing.T, clientAuth bool) *httptest.Server {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"flows":[{"name":"my-flow"}]}`)
+	}))
+	// handshake failures are expected by the tests
+	ts.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+
+	cert, err := tls.LoadX509KeyPair("./testdata/tls/server.crt", "./testdata/tls/server.key")
+	require.NoError(t, err)
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	if clientAuth {
+		pem, err := ioutil.ReadFile("./testdata/tls/ca.crt")
+		require.NoError(t, err)
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(pem)
+		ts.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+		ts.TLS.ClientCAs = pool
+	}
+
+	ts.StartTLS()
+	return ts
+}
+
+func TestTLS_ShouldReplaceContextCertificateAuthorityWithInsecureOption(t *testing.T) {
+	//given
+	ts := newTLSServer(t, false)
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n"+
+		"  certificate-authority: ./testdata/tls/ca.crt\n", ts.URL))
+	defer cleanup()
+
+	//when
+	_, err := executeCommand("get", "flows", "--config", config, "--insecure-skip-tls-verify")
+
+	//then
+	require.NoError(t, err)
+}
+
+func TestTLS_ShouldSwitchOffContextInsecureModeWithOption(t *testing.T) {
+	//given
+	ts := newTLSServer(t, false)
+	defer ts.Close()
+
+	config, cleanup := tempConfig(t, fmt.Sprintf("current-context: dev\ncontexts:\n- name: dev\n  url: %s\n"+
+		"  insecure-skip-tls-verify: true\n", ts.URL))
+	defer cleanup()
+
+	//when
+	_, insecureErr := executeCommand("get", "flows", "--config", config)
+	_, err := executeCommand("get", "flows", "--config", config, "--insecure-skip-tls-verify=false")
+
+	//then
+	require.NoError(t, insecureErr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signed by unknown authority")
+}
@@ -12,7 +12,7 @@ func newCmdUpload() *cobra.Command {
 		Example: exampleUpload,
 	}
 
-	cmd.SetUsageTemplate(usageTmplUpload)
+	cmd.SetUsageTemplate(usageTmplResource)
 	cmd.AddCommand(newCmdUploadFlow(), newCmdUploadDs())
 	return cmd
 }
@@ -30,7 +30,7 @@ const exampleUpload = `  # Upload a flow from my_flow.json file to flyte API spe
   # Upload a flow from my_flow.yaml file to flyte API at http://127.0.0.1:8080
   flyte upload flow -f ./my_flow.yaml --url http://127.0.0.1:8080`
 
-const usageTmplUpload = `Usage:
+const usageTmplResource = `Usage:
   {{.UseLine}}{{if .HasExample}}
 
 Examples:
@@ -4,11 +4,8 @@ import (
 	"github.com/spf13/cobra"
 	"fmt"
 	"net/http"
-	"github.com/HotelsDotCom/flyte/flytepath"
-	"io"
-	"os"
+	"errors"
 	httputl "net/http/httputil"
-	"github.com/spf13/viper"
 	"bytes"
 	"mime/multipart"
 	"net/textproto"
@@ -21,6 +18,8 @@ type dsItem struct {
 	description string
 	contentType string
 	filename    string
+	// value is read from the filename unless it is set
+	value []byte
 }
 
 var argsUploadDs dsItem
@@ -29,7 +28,7 @@ func newCmdUploadDs() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "datastore -f FILENAME",
 		Aliases: []string{"ds"},
-		Short:   "Upload a datastore item from a file",
+		Short:   "Upload a datastore item from a file, or many items from a directory",
 		Long:    longUploadDs,
 		RunE:    runUploadDs,
 	}
@@ -40,6 +39,8 @@ func newCmdUploadDs() *cobra.Command {
 	cmd.Flags().StringVarP(&argsUploadDs.name, flagName, "n", "", "item's name (default derived from the file name)")
 	cmd.Flags().StringVarP(&argsUploadDs.description, flagDescription, "d", "", "item's description")
 	cmd.Flags().StringVarP(&argsUploadDs.contentType, flagContentType, "c", "", "item's content type (default derived from the file extension)")
+	cmd.Flags().StringVar(&argsUploadDsBulk.manifest, flagManifest, "", "manifest with items' names, descriptions and content types (default "+manifestFile+" in the directory)")
+	cmd.Flags().IntVar(&argsUploadDsBulk.concurrency, flagConcurrency, defaultConcurrency, "number of items uploaded at the same time from a directory or glob")
 
 	return cmd
 }
@@ -48,66 +49,127 @@ const longUploadDs = `
 Upload a datastore item from a file or from stdin to a flyte API.
 Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
 
+The item's name is derived from the file name and its content type from the file extension
+unless they are set by the options. When reading from stdin (-f -) the --name option is
+required and JSON or YAML content type is detected from the content.
+Files with .enc extension encrypted by 'flyte ds encrypt' are decrypted in memory with the key
+set by the --key-file option, their name and content type are derived without the extension.
+
+When the file is a directory or a glob pattern, all the files are uploaded as datastore items
+at the same time, limited by the --concurrency option. Items are named after their files
+unless a manifest sets their name, description and content type. The manifest is set by the
+--manifest option, or it is the flyte-manifest.yaml file in the directory (see 'flyte apply --help'),
+its file paths are relative to its directory. Flow files are skipped unless the manifest lists them
+as datastore items, content type of files without extension is detected. A table with the result of every item is printed
+and the command fails when any of the items cannot be uploaded.
+
+The result is printed as "datastore/NAME created" or "datastore/NAME updated", or in the
+format set by the --output option. The raw flyte API response is printed to stderr with
+the --verbose option. With the --dry-run option the request is printed instead of sent.
+
 Examples:
   # Upload a datastore item from env.json file to flyte API specified by $FLYTE_API
   flyte upload ds -f ./env.json
 
   # Upload a datastore item from my-script.sh file to flyte API at http://127.0.0.1:8080
   flyte upload ds -f ./my-script.sh --url http://127.0.0.1:8080
+
+  # Upload a datastore item from stdin
+  cat ./env.json | flyte upload ds -f - --name env
+
+  # Upload all files in ./env directory as datastore items
+  flyte upload ds -f ./env
+
+  # Upload JSON files using the names and descriptions from a manifest
+  flyte upload ds -f './env/*.json' --manifest ./env/flyte-manifest.yaml
+
+  # Upload a datastore item and print only its kind and name
+  flyte upload ds -f ./env.json -o name
 `
 
 func runUploadDs(c *cobra.Command, args []string) error {
+	if err := checkOutput(); err != nil {
+		return err
+	}
+
+	if isBulkUpload(argsUploadDs.filename) {
+		return runUploadDsBulk(c)
+	}
+
+	if argsUploadDs.filename == "-" && argsUploadDs.name == "" {
+		return errors.New("cannot upload datastore item: --name is required when reading from stdin")
+	}
+
+	value, err := readDsValue(argsUploadDs.filename)
+	if err != nil {
+		return err
+	}
+	argsUploadDs.value = value
+
+	// encrypted file's name and content type are derived from its name without the encrypted extension
+	plain := plainFilename(argsUploadDs.filename)
 	if argsUploadDs.name == "" {
-		base := filepath.Base(argsUploadDs.filename)
-		ext := filepath.Ext(argsUploadDs.filename)
+		base := filepath.Base(plain)
+		ext := filepath.Ext(plain)
 		argsUploadDs.name = strings.TrimSuffix(base, ext)
 	}
 
 	if argsUploadDs.contentType == "" {
-		argsUploadDs.contentType = getContentType(argsUploadDs.filename)
+		argsUploadDs.contentType = getContentType(detectExt(plain, value))
 	}
 
-	req, err := newDsRequest(viper.GetString(flagURL), argsUploadDs)
+	req, err := newDsRequest(apiURL(), argsUploadDs)
 	if err != nil {
 		return err
 	}
+	if isDryRun() {
+		return printRequest(c, req)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
-
 	defer resp.Body.Close()
-	dump, err := httputl.DumpResponse(resp, true)
-	if err != nil {
-		return err
-	}
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		dump, err := httputl.DumpResponse(resp, true)
+		if err != nil {
+			return err
+		}
 		return fmt.Errorf("cannot upload datastore\n%s", dump)
 	}
 
-	_, err = fmt.Fprintf(c.OutOrStdout(), "%sLocation: %s\n", dump, resp.Request.URL.String())
-	return err
+	if err := printVerbose(c, resp); err != nil {
+		return err
+	}
+	return printUploadResult(c, newUploadResult("datastore", argsUploadDs.name, resp))
 }
 
 func newDsRequest(apiURL string, item dsItem) (*http.Request, error) {
-	file, err := os.Open(item.filename)
-	if err != nil {
-		return nil, err
+	value := item.value
+	if value == nil {
+		var err error
+		if value, err = readDsValue(item.filename); err != nil {
+			return nil, err
+		}
+	}
+
+	filename := filepath.Base(plainFilename(item.filename))
+	if item.filename == "-" {
+		filename = item.name
 	}
-	defer file.Close()
 
 	body := new(bytes.Buffer)
 	w := multipart.NewWriter(body)
 
-	h := newFormFileHeader("value", filepath.Base(item.filename), item.contentType)
+	h := newFormFileHeader("value", filename, item.contentType)
 	part, err := w.CreatePart(h)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err = io.Copy(part, file); err != nil {
+	if _, err = part.Write(value); err != nil {
 		return nil, err
 	}
 
@@ -136,5 +198,5 @@ func newFormFileHeader(fieldname, filename, contentType string) textproto.MIMEHe
 }
 
 func dsItemURL(apiURL, name string) string {
-	return fmt.Sprintf("%s%s/%s", apiURL, flytepath.DatastorePath, name)
+	return fmt.Sprintf("%s/%s", dsURL(apiURL), name)
 }
@@ -46,7 +46,7 @@ func TestUploadDs_ShouldUploadDsFromFile(t *testing.T) {
 	dsFile := "./testdata/env.json"
 
 	//when
-	output, err := executeCommand("upload", "ds", "-f", dsFile, "--url", ts.URL)
+	output, err := executeCommand("upload", "ds", "-f", dsFile, "--url", ts.URL, "-o", "json")
 	require.NoError(t, err)
 
 	//then
@@ -60,8 +60,8 @@ func TestUploadDs_ShouldUploadDsFromFile(t *testing.T) {
 	assert.Equal(t, wantContent, rec.fileBody)
 	assert.Equal(t, httputil.MediaTypeJson, rec.fileContentType)
 
-	l := fmt.Sprintf("Location: %s%s/%s", ts.URL, flytepath.DatastorePath, "env")
-	assert.Contains(t, output, l)
+	l := fmt.Sprintf("%s%s/%s", ts.URL, flytepath.DatastorePath, "env")
+	assert.Equal(t, "{\n\t\"kind\": \"datastore\",\n\t\"name\": \"env\",\n\t\"location\": \""+l+"\",\n\t\"status\": \"created\"\n}\n", output)
 }
 
 func TestUploadDs_ShouldUploadDsFromFileWithDefaultsOverriddenByFlags(t *testing.T) {
@@ -106,8 +106,7 @@ func TestUploadDs_ShouldUploadDsFromFileWithDefaultsOverriddenByFlags(t *testing
 	assert.Equal(t, description, rec.description)
 	assert.Equal(t, contentType, rec.contentType)
 
-	l := fmt.Sprintf("Location: %s%s/%s", ts.URL, flytepath.DatastorePath, name)
-	assert.Contains(t, output, l)
+	assert.Equal(t, "datastore/my-data created\n", output)
 }
 
 func TestUploadDs_ShouldCreateResource(t *testing.T) {
@@ -122,8 +121,7 @@ func TestUploadDs_ShouldCreateResource(t *testing.T) {
 	require.NoError(t, err)
 
 	//then
-	l := fmt.Sprintf("Location: %s%s/%s", ts.URL, flytepath.DatastorePath, "env")
-	assert.Contains(t, output, l)
+	assert.Equal(t, "datastore/env created\n", output)
 }
 
 func TestUploadDs_ShouldUpdateResource(t *testing.T) {
@@ -138,8 +136,7 @@ func TestUploadDs_ShouldUpdateResource(t *testing.T) {
 	require.NoError(t, err)
 
 	//then
-	l := fmt.Sprintf("Location: %s%s/%s", ts.URL, flytepath.DatastorePath, "env")
-	assert.Contains(t, output, l)
+	assert.Equal(t, "datastore/env updated\n", output)
 }
 
 func TestUploadDs_ShouldErrorForNon201Or204Response(t *testing.T) {
@@ -156,3 +153,47 @@ func TestUploadDs_ShouldErrorForNon201Or204Response(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "404 Not Found")
 }
+
+func TestUploadDs_ShouldUploadDsFromStdin(t *testing.T) {
+	//given
+	rec := struct {
+		reqURL          string
+		fileBody        []byte
+		fileContentType string
+	}{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.reqURL = r.URL.String()
+
+		f, h, err := r.FormFile("value")
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+
+		rec.fileBody, _ = ioutil.ReadAll(f)
+		rec.fileContentType = h.Header.Get(httputil.HeaderContentType)
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+	defer replaceStdin(`{"channel":"123"}`)()
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", "-", "--name", "env", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, flytepath.DatastorePath+"/env", rec.reqURL)
+	assert.Equal(t, `{"channel":"123"}`, string(rec.fileBody))
+	assert.Equal(t, httputil.MediaTypeJson, rec.fileContentType)
+	assert.Equal(t, "datastore/env created\n", output)
+}
+
+func TestUploadDs_ShouldRequireNameWhenReadingFromStdin(t *testing.T) {
+	defer replaceStdin("hello")()
+
+	_, err := executeCommand("upload", "ds", "-f", "-", "--url", "http://localhost:1")
+	require.Error(t, err)
+
+	assert.Equal(t, "cannot upload datastore item: --name is required when reading from stdin", err.Error())
+}
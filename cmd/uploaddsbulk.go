@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const defaultConcurrency = 4
+
+var argsUploadDsBulk = struct {
+	manifest    string
+	concurrency int
+}{}
+
+// isBulkUpload is true when the filename is a directory or a glob pattern of many datastore item files
+func isBulkUpload(filename string) bool {
+	if filename == "-" {
+		return false
+	}
+	return strings.ContainsAny(filename, "*?[") || isDir(filename)
+}
+
+func runUploadDsBulk(c *cobra.Command) error {
+	if argsUploadDs.name != "" {
+		return errors.New("cannot upload datastore items: --name cannot be used with a directory or glob")
+	}
+	if argsUploadDsBulk.concurrency < 1 {
+		return fmt.Errorf("invalid --%s value %d, it must be at least 1", flagConcurrency, argsUploadDsBulk.concurrency)
+	}
+
+	items, err := readDsItems(argsUploadDs.filename, argsUploadDsBulk.manifest)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("cannot upload datastore items: no files found in %s", argsUploadDs.filename)
+	}
+
+	for i := range items {
+		if items[i].description == "" {
+			items[i].description = argsUploadDs.description
+		}
+		if items[i].contentType == "" {
+			items[i].contentType = argsUploadDs.contentType
+		}
+		if items[i].contentType == "" {
+			items[i].contentType = getContentType(detectExt(plainFilename(items[i].filename), items[i].value))
+		}
+	}
+
+	if isDryRun() {
+		for _, item := range items {
+			req, err := newDsRequest(apiURL(), item)
+			if err != nil {
+				return err
+			}
+			if err := printRequest(c, req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	results, errs := uploadDsItems(c, apiURL(), items, argsUploadDsBulk.concurrency)
+	failed, err := reportDsResults(c, items, results, errs)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("cannot upload %d of %d datastore item(s)", failed, len(items))
+	}
+	return nil
+}
+
+// reportDsResults prints the results of uploaded items, see printBulkResults.
+// Errors are printed to stderr and the items are reported as failed, it returns the number of failed items.
+func reportDsResults(c *cobra.Command, items []dsItem, results []uploadResult, errs []error) (int, error) {
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(c.OutOrStderr(), "cannot upload datastore/%s from %s\n%s\n", items[i].name, items[i].filename, err)
+			results[i] = uploadResult{Kind: kindDatastore, Name: items[i].name, Status: statusFailed}
+			failed++
+		}
+	}
+
+	return failed, printBulkResults(c, results)
+}
+
+// printBulkResults prints results of many resources as a table unless --output option is set
+func printBulkResults(c *cobra.Command, results []uploadResult) error {
+	if viper.GetString(flagOutput) == "" {
+		return printResultTable(c, results)
+	}
+	return printUploadResults(c, results)
+}
+
+// readDsItems reads datastore items from files in the directory or matching the glob pattern, sorted by name.
+// Files in the manifest are relative to its directory, a directory's own manifest is used by default.
+// Flow files are skipped the same way as apply classifies them, unless the manifest lists them as datastore items.
+func readDsItems(pattern, manifestFilename string) ([]dsItem, error) {
+	files, err := matchFiles(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &applyManifest{}
+	dir := filepath.Dir(pattern)
+	switch {
+	case manifestFilename != "":
+		if manifest, err = readManifestFile(manifestFilename); err != nil {
+			return nil, err
+		}
+		dir = filepath.Dir(manifestFilename)
+	case isDir(pattern):
+		if manifest, err = readManifest(pattern); err != nil {
+			return nil, err
+		}
+		manifestFilename = filepath.Join(pattern, manifestFile)
+		dir = pattern
+	}
+
+	var items []dsItem
+	names := map[string]string{}
+	for _, filename := range files {
+		if filepath.Clean(filename) == filepath.Clean(manifestFilename) {
+			continue
+		}
+		rel, err := relPath(dir, filename)
+		if err != nil {
+			return nil, err
+		}
+		if matchAny(manifest.Ignore, rel) {
+			continue
+		}
+
+		value, err := readDsValue(filename)
+		if err != nil {
+			return nil, err
+		}
+		if !manifest.isDsFile(rel) && (matchAny(manifest.Flows, rel) || isFlow(plainFilename(filename), value)) {
+			continue
+		}
+
+		item := manifest.dsItem(filename, rel)
+		item.value = value
+		if f, ok := names[item.name]; ok {
+			return nil, fmt.Errorf("%s/%s is defined in both %s and %s", kindDatastore, item.name, f, filename)
+		}
+		names[item.name] = filename
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].name < items[j].name
+	})
+	return items, nil
+}
+
+// matchFiles lists files in the directory, or files matching the glob pattern
+func matchFiles(pattern string) ([]string, error) {
+	if isDir(pattern) {
+		return listFiles(pattern)
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("cannot upload datastore items: %v", err)
+	}
+	var files []string
+	for _, m := range matches {
+		if !isDir(m) {
+			files = append(files, m)
+		}
+	}
+	return files, nil
+}
+
+func isDir(filename string) bool {
+	info, err := os.Stat(filename)
+	return err == nil && info.IsDir()
+}
+
+// isDsFile is true when the manifest lists the file as a datastore item
+func (m *applyManifest) isDsFile(rel string) bool {
+	for _, i := range m.Datastore {
+		if filepath.ToSlash(filepath.Clean(i.File)) == rel {
+			return true
+		}
+	}
+	return false
+}
+
+// dsItem is the datastore item from the file, named after the file unless the manifest sets its name
+func (m *applyManifest) dsItem(filename, rel string) dsItem {
+	item := dsItem{filename: filename}
+	for _, i := range m.Datastore {
+		if filepath.ToSlash(filepath.Clean(i.File)) == rel {
+			item.name = i.Name
+			item.description = i.Description
+			item.contentType = i.ContentType
+		}
+	}
+
+	if item.name == "" {
+		base := filepath.Base(plainFilename(filename))
+		item.name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return item
+}
+
+// uploadDsItems uploads the items by a bounded number of workers, results and errors are in the order of the items
+func uploadDsItems(c *cobra.Command, apiURL string, items []dsItem, workers int) ([]uploadResult, []error) {
+	results := make([]uploadResult, len(items))
+	errs := make([]error, len(items))
+
+	// mu keeps verbose responses of the workers from mixing
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for w := 0; w < workers && w < len(items); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = uploadDsItem(c, &mu, apiURL, items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, errs
+}
+
+func uploadDsItem(c *cobra.Command, mu *sync.Mutex, apiURL string, item dsItem) (uploadResult, error) {
+	req, err := newDsRequest(apiURL, item)
+	if err != nil {
+		return uploadResult{}, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return uploadResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return uploadResult{}, newResponseError(resp)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := printVerbose(c, resp); err != nil {
+		return uploadResult{}, err
+	}
+	return newUploadResult(kindDatastore, item.name, resp), nil
+}
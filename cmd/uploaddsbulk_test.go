@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+	"github.com/HotelsDotCom/flyte/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadDsBulk_ShouldUploadDirectoryUsingItsManifest(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", "./testdata/apply", "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	l := ts.URL + flytepath.DatastorePath
+	assert.Equal(t, "KIND       NAME   STATUS   LOCATION\n"+
+		"datastore  env    created  "+l+"/env\n"+
+		"datastore  hello  created  "+l+"/hello\n", output)
+
+	assert.Equal(t, "Slack environment", api.ds["env"].description)
+	assert.Equal(t, httputil.MediaTypeJson, api.ds["env"].contentType)
+	assert.Equal(t, "application/x-sh", api.ds["hello"].contentType)
+	assert.NotContains(t, api.ds, "README")
+	assert.NotContains(t, api.ds, "token")
+	assert.NotContains(t, api.ds, "deploy")
+	assert.NotContains(t, api.ds, "status")
+	assert.Empty(t, api.flows)
+}
+
+func TestUploadDsBulk_ShouldDetectContentTypeAndUploadFlowListedAsDatastoreItem(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "flyte-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "env"), []byte(`{"channel": "123"}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "template.yaml"), []byte("name: template\nsteps: []\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, manifestFile), []byte("datastore:\n- file: template.yaml\n"), 0644))
+
+	//when
+	_, err = executeCommand("upload", "ds", "-f", dir, "--url", ts.URL)
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, httputil.MediaTypeJson, api.ds["env"].contentType)
+	assert.Equal(t, httputil.MediaTypeYaml, api.ds["template"].contentType)
+}
+
+func TestUploadDsBulk_ShouldUploadGlobWithManifest(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", "./testdata/apply/ds/*.json",
+		"--manifest", "./testdata/apply/flyte-manifest.yaml", "--description", "default", "--url", ts.URL, "-o", "name")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "datastore/env\n", output)
+	assert.Equal(t, "Slack environment", api.ds["env"].description)
+}
+
+func TestUploadDsBulk_ShouldReportFailedItemsAndContinue(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.failPut = "hello"
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", "./testdata/apply/ds", "--url", ts.URL, "--concurrency", "1")
+
+	//then
+	require.Error(t, err)
+	assert.Equal(t, "cannot upload 1 of 2 datastore item(s)", err.Error())
+	assert.Contains(t, output, "cannot upload datastore/hello from testdata/apply/ds/hello.sh\nHTTP/1.1 500 Internal Server Error")
+	assert.Contains(t, output, "datastore  hello      failed   \n")
+	assert.Contains(t, api.ds, "slack-env")
+}
+
+func TestUploadDsBulk_ShouldPrintRequestsOnDryRun(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "ds", "-f", "./testdata/apply/ds", "--url", ts.URL, "--dry-run")
+
+	//then
+	require.NoError(t, err)
+	assert.Contains(t, output, "PUT "+ts.URL+flytepath.DatastorePath+"/hello\n")
+	assert.Contains(t, output, "PUT "+ts.URL+flytepath.DatastorePath+"/slack-env\n")
+	assert.Empty(t, api.requests)
+}
+
+func TestUploadDsBulk_ShouldFailForNameOption(t *testing.T) {
+	_, err := executeCommand("upload", "ds", "-f", "./testdata/apply/ds", "--name", "env", "--url", "http://localhost:1")
+
+	require.Error(t, err)
+	assert.Equal(t, "cannot upload datastore items: --name cannot be used with a directory or glob", err.Error())
+}
+
+func TestUploadDsBulk_ShouldFailWhenGlobMatchesNoFiles(t *testing.T) {
+	_, err := executeCommand("upload", "ds", "-f", "./testdata/apply/ds/*.xml", "--url", "http://localhost:1")
+
+	require.Error(t, err)
+	assert.Equal(t, "cannot upload datastore items: no files found in ./testdata/apply/ds/*.xml", err.Error())
+}
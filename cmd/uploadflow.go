@@ -3,20 +3,28 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 	"fmt"
-	"github.com/spf13/viper"
 	"net/http"
 	"github.com/HotelsDotCom/flyte/flytepath"
-	"os"
-	httputl "net/http/httputil"
+	"bytes"
 	"github.com/HotelsDotCom/flyte/httputil"
 	"errors"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
 )
 
 var argsUploadFlow = struct {
 	filename    string
 	contentType string
+	checkPacks  string
+	overwrite   bool
 }{}
 
+const (
+	checkPacksOff  = "off"
+	checkPacksWarn = "warn"
+	checkPacksFail = "fail"
+)
+
 func newCmdUploadFlow() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "flow -f FILENAME",
@@ -29,54 +37,179 @@ func newCmdUploadFlow() *cobra.Command {
 	cmd.MarkFlagRequired(flagFilename)
 
 	cmd.Flags().StringVarP(&argsUploadFlow.contentType, flagContentType, "c", "", "flow file content type (default derived from the file extension)")
+	cmd.Flags().StringVar(&argsUploadFlow.checkPacks, flagCheckPacks, checkPacksOff, "check steps against packs registered in flyte API before upload. One of: off|warn|fail")
+	cmd.Flags().BoolVar(&argsUploadFlow.overwrite, flagOverwrite, false, "replace the flow if it already exists (alias --upsert)")
+	cmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "upsert" {
+			name = flagOverwrite
+		}
+		return pflag.NormalizedName(name)
+	})
 
 	return cmd
 }
 
 const longUploadFlow = `
-Upload flow from a file to a flyte API. File must be in JSON or YAML format.
+Upload flow from a file or from stdin (-f -) to a flyte API. File must be in JSON or YAML format,
+the format of stdin is detected from the content unless --content-type option is set.
 Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
 
+The result is printed as "flow/NAME created", or in the format set by the --output option.
+The raw flyte API response is printed to stderr with the --verbose option.
+With the --dry-run option the requests are printed instead of sent.
+
+With --overwrite (or --upsert) option an existing flow with the same name is replaced,
+the result reports whether the flow was created or updated. Flows are replaced by deleting
+and creating them again, when the new flow is refused by the flyte API the previous one is
+created again. The flow is always parsed before the existing one is deleted.
+
+With --check-packs option every step's event and command is checked to be declared by
+a pack registered in the flyte API. Problems are printed as warnings (warn) or stop
+the upload (fail).
+
 Examples:
   # Upload a flow from my_flow.json file to flyte api specified by $FLYTE_API
   flyte upload flow -f ./my_flow.json
 
   # Upload a flow from my_flow.yaml file to flyte api at http://127.0.0.1:8080
   flyte upload flow -f ./my_flow.yaml --url http://127.0.0.1:8080
+
+  # Upload a flow from stdin
+  cat ./my_flow.yaml | flyte upload flow -f -
+
+  # Upload a flow only if all events and commands are declared by registered packs
+  flyte upload flow -f ./my_flow.yaml --check-packs fail
+
+  # Create my_flow or replace it if it already exists
+  flyte upload flow -f ./my_flow.yaml --overwrite
+
+  # Upload a flow and print the result as json
+  flyte upload flow -f ./my_flow.yaml -o json
 `
 
 func runUploadFlow(c *cobra.Command, args []string) error {
+	if err := checkOutput(); err != nil {
+		return err
+	}
+
+	data, err := readFile(argsUploadFlow.filename)
+	if err != nil {
+		return err
+	}
 
 	if argsUploadFlow.contentType == "" {
-		argsUploadFlow.contentType = getContentType(argsUploadFlow.filename)
+		argsUploadFlow.contentType = getContentType(detectExt(argsUploadFlow.filename, data))
 	}
 	if argsUploadFlow.contentType != httputil.MediaTypeJson &&
 		argsUploadFlow.contentType != httputil.MediaTypeYaml {
 		return errors.New("cannot upload flow: unsupported file type it must be JSON or YAML")
 	}
 
-	body, err := os.Open(argsUploadFlow.filename)
-	if err != nil {
+	if err := checkFlowPacks(c, data); err != nil {
 		return err
 	}
 
-	resp, err := client.Post(flowsURL(viper.GetString(flagURL)), argsUploadFlow.contentType, body)
+	// the flow is parsed before the existing one is deleted, so it is not replaced by a broken file
+	if _, err := parseFlow(argsUploadFlow.filename, data); err != nil {
+		return fmt.Errorf("cannot upload flow: %s", parseError(argsUploadFlow.filename, data, err).format(argsUploadFlow.filename))
+	}
+
+	name := flowName(data)
+	var previous map[string]interface{}
+	if argsUploadFlow.overwrite {
+		if name == "" {
+			return errors.New("cannot upload flow: name is missing")
+		}
+		if previous, err = fetchFlow(flowURL(apiURL(), name)); err != nil {
+			return fmt.Errorf("cannot upload flow\n%s", err)
+		}
+	}
+
+	if isDryRun() {
+		return printUploadFlowRequests(c, name, data, previous != nil)
+	}
+
+	var resp *http.Response
+	if previous != nil {
+		resp, err = recreateFlow(apiURL(), name, argsUploadFlow.contentType, data, previous)
+	} else {
+		resp, err = createFlow(apiURL(), argsUploadFlow.contentType, data)
+	}
 	if err != nil {
+		return fmt.Errorf("cannot upload flow\n%s", err)
+	}
+	defer resp.Body.Close()
+
+	if err := printVerbose(c, resp); err != nil {
 		return err
 	}
+	result := newUploadResult(kindFlow, name, resp)
+	if previous != nil {
+		result.Status = statusUpdated
+	}
+	return printUploadResult(c, result)
+}
 
-	defer resp.Body.Close()
-	dump, err := httputl.DumpResponse(resp, true)
+// printUploadFlowRequests prints the requests which would upload the flow, the existing flow is deleted first
+func printUploadFlowRequests(c *cobra.Command, name string, data []byte, exists bool) error {
+	if exists {
+		req, err := http.NewRequest(http.MethodDelete, flowURL(apiURL(), name), nil)
+		if err != nil {
+			return err
+		}
+		if err := printRequest(c, req); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, flowsURL(apiURL()), bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
+	req.Header.Set(httputil.HeaderContentType, argsUploadFlow.contentType)
+	return printRequest(c, req)
+}
+
+// flowName reads the name from JSON or YAML flow definition, empty if the flow cannot be read
+func flowName(data []byte) string {
+	flow := struct {
+		Name string `json:"name"`
+	}{}
+	yaml.Unmarshal(data, &flow)
+	return flow.Name
+}
+
+// checkFlowPacks checks the flow against registered packs as requested by the --check-packs option
+func checkFlowPacks(c *cobra.Command, data []byte) error {
+	mode := argsUploadFlow.checkPacks
+	switch mode {
+	case checkPacksOff:
+		return nil
+	case checkPacksWarn, checkPacksFail:
+	default:
+		return fmt.Errorf("cannot upload flow: invalid --%s value %q, it must be one of: off|warn|fail", flagCheckPacks, mode)
+	}
 
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("cannot upload flow\n%s", dump)
+	flow, err := parseFlow(argsUploadFlow.filename, data)
+	var errs []validationError
+	if err == nil {
+		errs, err = validateFlowPacks(apiURL(), data, flow)
+	}
+	if err != nil {
+		if mode == checkPacksFail {
+			return fmt.Errorf("cannot upload flow: cannot check packs: %v", err)
+		}
+		_, err = fmt.Fprintf(c.OutOrStdout(), "warn: cannot check packs: %v\n", err)
+		return err
 	}
 
-	_, err = fmt.Fprintf(c.OutOrStdout(), "%s", dump)
-	return err
+	for _, e := range errs {
+		fmt.Fprintf(c.OutOrStdout(), "%s: %s\n", mode, e.format(argsUploadFlow.filename))
+	}
+	if mode == checkPacksFail && len(errs) > 0 {
+		return fmt.Errorf("cannot upload flow: %d pack check error(s) found", len(errs))
+	}
+	return nil
 }
 
 func flowsURL(apiURL string) string {
@@ -42,7 +42,7 @@ func TestUploadFlow_ShouldUploadFlowFromJsonFile(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, wantBody, rec.body)
 
-	assert.Contains(t, output, "Location: "+flytepath.FlowsPath+"/my-flow")
+	assert.Equal(t, "flow/my-flow created\n", output)
 }
 
 func TestUploadFlow_ShouldFailWhenFlyteAPIReturnsNon201(t *testing.T) {
@@ -95,7 +95,7 @@ func TestUploadFlow_ShouldUploadFlowFromYamlFile(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, wantBody, rec.body)
 
-	assert.Contains(t, output, "Location: "+flytepath.FlowsPath+"/my-flow")
+	assert.Equal(t, "flow/my-flow created\n", output)
 }
 
 func TestUploadFlow_ShouldUploadFlowFromYmlFile(t *testing.T) {
@@ -124,5 +124,185 @@ func TestUploadFlow_ShouldUploadFlowFromYmlFile(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, wantBody, rec.body)
 
-	assert.Contains(t, output, "Location: "+flytepath.FlowsPath+"/my-flow")
+	assert.Equal(t, "flow/my-flow created\n", output)
+}
+
+func TestUploadFlow_ShouldWarnAboutPacksAndUpload(t *testing.T) {
+	//given
+	uploaded := false
+	ts := newPacksServer(`{"id":"Slack","name":"Slack","events":[{"name":"ReceivedMessage"}]}`, func(w http.ResponseWriter, r *http.Request) {
+		uploaded = r.Method == http.MethodPost && r.URL.Path == flytepath.FlowsPath
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/valid-flow.yaml", "--check-packs", "warn", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.True(t, uploaded)
+	assert.Contains(t, output, "warn: ./testdata/valid-flow.yaml:5: step \"status\": command \"SendMessage\" is not declared by live \"Slack\" pack\n")
+	assert.Contains(t, output, "warn: ./testdata/valid-flow.yaml:19: step \"confirm\": event \"MessageSent\" is not declared by live \"Slack\" pack\n")
+}
+
+func TestUploadFlow_ShouldNotUploadWhenPackCheckFails(t *testing.T) {
+	//given
+	uploaded := false
+	ts := newPacksServer(`{"id":"Slack","name":"Slack","events":[{"name":"ReceivedMessage"}]}`, func(w http.ResponseWriter, r *http.Request) {
+		uploaded = true
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/valid-flow.yaml", "--check-packs", "fail", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.False(t, uploaded)
+	assert.Equal(t, "cannot upload flow: 3 pack check error(s) found", err.Error())
+	assert.Contains(t, output, "fail: ./testdata/valid-flow.yaml:5: step \"status\": command \"SendMessage\" is not declared by live \"Slack\" pack\n")
+}
+
+func TestUploadFlow_ShouldUploadWhenPacksCannotBeCheckedInWarnMode(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/valid-flow.yaml", "--check-packs", "warn", "--url", ts.URL)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "warn: cannot check packs: cannot list packs\nHTTP/1.1 500 Internal Server Error")
+}
+
+func TestUploadFlow_ShouldFailForExistingFlowWithoutOverwrite(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["my-flow"] = map[string]interface{}{"name": "my-flow"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.yaml", "--url", ts.URL)
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot upload flow\nHTTP/1.1 409 Conflict")
+}
+
+func TestUploadFlow_ShouldReplaceExistingFlowWithOverwrite(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["my-flow"] = map[string]interface{}{"name": "my-flow"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.yaml", "--url", ts.URL, "--overwrite")
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, "flow/my-flow updated\n", output)
+	assert.Equal(t, []string{
+		"GET " + flytepath.FlowsPath + "/my-flow",
+		"DELETE " + flytepath.FlowsPath + "/my-flow",
+		"POST " + flytepath.FlowsPath,
+	}, api.requests)
+	assert.Contains(t, api.flows["my-flow"], "steps")
+}
+
+func TestUploadFlow_ShouldCreateMissingFlowWithUpsert(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.yaml", "--url", ts.URL, "--upsert", "-o", "json")
+
+	//then
+	require.NoError(t, err)
+	assert.Contains(t, output, `"status": "created"`)
+	assert.Contains(t, api.flows, "my-flow")
+}
+
+func TestUploadFlow_ShouldPrintDeleteAndCreateRequestsOnDryRunOverwrite(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["my-flow"] = map[string]interface{}{"name": "my-flow"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.yaml", "--url", ts.URL, "--overwrite", "--dry-run")
+
+	//then
+	require.NoError(t, err)
+	assert.Contains(t, output, "DELETE "+ts.URL+flytepath.FlowsPath+"/my-flow\nBody: none\nPOST "+ts.URL+flytepath.FlowsPath+"\n")
+	assert.Equal(t, []string{"GET " + flytepath.FlowsPath + "/my-flow"}, api.requests)
+}
+
+func TestUploadFlow_ShouldUploadFlowFromStdin(t *testing.T) {
+	//given
+	rec := requestRec{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.request = *r
+		rec.body, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("Location", flytepath.FlowsPath+"/my-flow")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	flow, err := ioutil.ReadFile("./testdata/my-flow.yaml")
+	require.NoError(t, err)
+	defer replaceStdin(string(flow))()
+
+	//when
+	output, err := executeCommand("upload", "flow", "-f", "-", "--url", ts.URL)
+	require.NoError(t, err)
+
+	//then
+	assert.Equal(t, httputil.MediaTypeYaml, rec.request.Header.Get(httputil.HeaderContentType))
+	assert.Equal(t, flow, rec.body)
+	assert.Equal(t, "flow/my-flow created\n", output)
+}
+
+func TestUploadFlow_ShouldRestorePreviousFlowWhenOverwriteIsRefused(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["my-flow"] = map[string]interface{}{"name": "my-flow", "description": "old"}
+	api.rejectFlow = "My awesome flow"
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("upload", "flow", "-f", "./testdata/my-flow.yaml", "--url", ts.URL, "--overwrite")
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot upload flow\nflow cannot be replaced, previous flow is restored\nHTTP/1.1 400 Bad Request")
+	assert.Equal(t, map[string]interface{}{"name": "my-flow", "description": "old"}, api.flows["my-flow"])
+}
+
+func TestUploadFlow_ShouldNotDeleteExistingFlowForUnparsableFile(t *testing.T) {
+	//given
+	api := newFakeAPI()
+	api.flows["broken"] = map[string]interface{}{"name": "broken"}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	//when
+	_, err := executeCommand("upload", "flow", "-f", "./testdata/invalid-flow.json", "--url", ts.URL, "--overwrite")
+
+	//then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot upload flow: ./testdata/invalid-flow.json:")
+	assert.Empty(t, api.requests)
+	assert.Contains(t, api.flows, "broken")
 }
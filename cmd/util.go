@@ -4,7 +4,6 @@ import (
 	"path/filepath"
 	"github.com/HotelsDotCom/flyte/httputil"
 	"io/ioutil"
-	"os"
 	"strings"
 	"bufio"
 	"bytes"
@@ -26,7 +25,7 @@ func getContentType(filename string) string {
 
 func readFile(filename string) ([]byte, error) {
 	if filename == "-" {
-		return ioutil.ReadAll(os.Stdin)
+		return ioutil.ReadAll(stdin)
 	}
 	return ioutil.ReadFile(filename)
 }
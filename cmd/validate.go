@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCmdValidate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "validate TYPE",
+		Short:   "Validate a resource from a file or from stdin",
+		Long:    longValidate,
+		Example: exampleValidate,
+	}
+
+	cmd.SetUsageTemplate(usageTmplResource)
+	cmd.AddCommand(newCmdValidateFlow())
+	return cmd
+}
+
+const longValidate = `
+
+Validate a resource from a file or from stdin without contacting a flyte API. Valid resource types include:
+
+  * flow`
+
+const exampleValidate = `  # Validate a flow from my_flow.yaml file
+  flyte validate flow -f ./my_flow.yaml`
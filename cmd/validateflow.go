@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/HotelsDotCom/flyte/execution"
+	"github.com/flosch/pongo2"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+var argsValidateFlow = struct {
+	filename string
+	remote   bool
+}{}
+
+func newCmdValidateFlow() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flow -f FILENAME",
+		Short: "Validate a flow from a file or from stdin",
+		Long:  longValidateFlow,
+		RunE:  runValidateFlow,
+	}
+
+	cmd.Flags().StringVarP(&argsValidateFlow.filename, flagFilename, "f", "", "filename of the file with flow to validate")
+	cmd.MarkFlagRequired(flagFilename)
+
+	cmd.Flags().BoolVar(&argsValidateFlow.remote, flagRemote, false, "check events and commands against packs registered in flyte API")
+	return cmd
+}
+
+const longValidateFlow = `
+Validate a flow from a file without contacting a flyte API. File must be in JSON or YAML format.
+
+The flow is parsed into flyte's execution types and checked for:
+  * required fields: flow name, step ids, event and command pack names and names
+  * duplicate step ids
+  * dependsOn references to steps which do not exist
+  * templates in criteria, context and command input which do not compile
+
+Errors are reported with file name and line number where possible.
+
+With --remote option the packs registered in a flyte API are fetched and every step's
+event and command is checked to be declared by a live pack with matching name and labels,
+packs with other status (e.g. warning) are reported as not live.
+Flyte API could be specified by setting $FLYTE_API or overridden by the --url option
+
+Examples:
+  # Validate a flow from my_flow.yaml file
+  flyte validate flow -f ./my_flow.yaml
+
+  # Validate a flow from my_flow.yaml file against packs registered in flyte API at http://127.0.0.1:8080
+  flyte validate flow -f ./my_flow.yaml --remote --url http://127.0.0.1:8080
+
+  # Validate a flow from stdin
+  cat ./my_flow.json | flyte validate flow -f -
+`
+
+func runValidateFlow(c *cobra.Command, args []string) error {
+	data, err := readFile(argsValidateFlow.filename)
+	if err != nil {
+		return err
+	}
+
+	flow, errs := validateFlow(argsValidateFlow.filename, data)
+	if len(errs) == 0 && argsValidateFlow.remote {
+		errs, err = validateFlowPacks(apiURL(), data, flow)
+		if err != nil {
+			return err
+		}
+	}
+	return reportValidation(c, argsValidateFlow.filename, flow, errs)
+}
+
+// reportValidation prints validation errors and returns an error if there are any
+func reportValidation(c *cobra.Command, filename string, flow *flowDef, errs []validationError) error {
+	for _, e := range errs {
+		fmt.Fprintln(c.OutOrStdout(), e.format(filename))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid flow: %d error(s) found", len(errs))
+	}
+
+	_, err := fmt.Fprintf(c.OutOrStdout(), "flow %q is valid\n", flow.Name)
+	return err
+}
+
+// flowDef is a flow definition as it is uploaded to flyte API
+type flowDef struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Steps       []execution.Step `json:"steps"`
+}
+
+type validationError struct {
+	line int
+	msg  string
+}
+
+func (e validationError) format(filename string) string {
+	if e.line > 0 {
+		return fmt.Sprintf("%s:%d: %s", filename, e.line, e.msg)
+	}
+	return fmt.Sprintf("%s: %s", filename, e.msg)
+}
+
+// validateFlow parses the flow from data and checks it without contacting flyte API
+func validateFlow(filename string, data []byte) (*flowDef, []validationError) {
+	flow, err := parseFlow(filename, data)
+	if err != nil {
+		return nil, []validationError{parseError(filename, data, err)}
+	}
+
+	v := flowValidator{lines: strings.Split(string(data), "\n")}
+	v.validate(flow)
+	return flow, v.errs
+}
+
+func parseFlow(filename string, data []byte) (*flowDef, error) {
+	flow := &flowDef{}
+	switch detectExt(filename, data) {
+	case ".json":
+		return flow, json.Unmarshal(data, flow)
+	case ".yaml", ".yml":
+		return flow, yaml.Unmarshal(data, flow)
+	default:
+		return nil, errors.New("unsupported file type it must be JSON or YAML")
+	}
+}
+
+// parseError adds line number to JSON errors, YAML errors already include it
+func parseError(filename string, data []byte, err error) validationError {
+	offset := int64(-1)
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+	}
+
+	if offset < 0 || detectExt(filename, data) != ".json" {
+		return validationError{msg: fmt.Sprintf("cannot parse flow: %v", err)}
+	}
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	return validationError{line: line, msg: fmt.Sprintf("cannot parse flow: %v", err)}
+}
+
+type flowValidator struct {
+	lines []string
+	errs  []validationError
+}
+
+func (v *flowValidator) errorf(line int, format string, a ...interface{}) {
+	v.errs = append(v.errs, validationError{line: line, msg: fmt.Sprintf(format, a...)})
+}
+
+func (v *flowValidator) validate(flow *flowDef) {
+	if flow.Name == "" {
+		v.errorf(0, "flow name is required")
+	}
+	if len(flow.Steps) == 0 {
+		v.errorf(0, "flow must have at least one step")
+	}
+
+	ids := map[string]int{}
+	stepLines := v.stepLines(flow)
+	for i, s := range flow.Steps {
+		name := stepName(i, s)
+		line := stepLines[i]
+
+		if s.ID == "" {
+			v.errorf(line, "%s: id is required", name)
+		} else if first, ok := ids[s.ID]; ok {
+			v.errorf(line, "%s: duplicate step id, already used by step[%d]", name, first)
+		} else {
+			ids[s.ID] = i
+		}
+
+		v.required(line, name, "event.packName", s.Event.PackName)
+		v.required(line, name, "event.name", s.Event.Name)
+		v.required(line, name, "command.packName", s.Command.PackName)
+		v.required(line, name, "command.name", s.Command.Name)
+
+		v.template(line, name, "criteria", s.Criteria)
+		for _, k := range sortedKeys(s.Context) {
+			v.template(line, name, "context."+k, s.Context[k])
+		}
+		v.input(line, name, "command.input", s.Command.Input)
+	}
+
+	for i, s := range flow.Steps {
+		line := stepLines[i]
+		if l := v.findLine(line, "dependsOn"); line > 0 && l > 0 {
+			line = l
+		}
+		for _, d := range s.DependsOn {
+			dLine := line
+			if l := v.findLine(line, d); line > 0 && l > 0 {
+				dLine = l
+			}
+			if d == s.ID {
+				v.errorf(dLine, "step[%d]: step cannot depend on itself", i)
+			} else if _, ok := ids[d]; !ok {
+				v.errorf(dLine, "step[%d]: dependsOn refers to unknown step %q", i, d)
+			}
+		}
+	}
+}
+
+// stepLines returns line numbers of steps with ids, 0 if line cannot be found
+func (v *flowValidator) stepLines(flow *flowDef) []int {
+	lines := make([]int, len(flow.Steps))
+	stepLine := v.findLine(1, "steps")
+	for i, s := range flow.Steps {
+		if s.ID == "" {
+			continue
+		}
+		lines[i] = v.findLine(stepLine+1, "id", s.ID)
+		if lines[i] > 0 {
+			stepLine = lines[i]
+		}
+	}
+	return lines
+}
+
+func stepName(i int, s execution.Step) string {
+	if s.ID == "" {
+		return fmt.Sprintf("step[%d]", i)
+	}
+	return fmt.Sprintf("step %q", s.ID)
+}
+
+func (v *flowValidator) required(line int, step, field, value string) {
+	if value == "" {
+		v.errorf(line, "%s: %s is required", step, field)
+	}
+}
+
+// template compiles the template and reports errors at the line of the template if it can be found
+func (v *flowValidator) template(line int, step, field, tmpl string) {
+	if tmpl == "" {
+		return
+	}
+	if _, err := pongo2.FromString(tmpl); err != nil {
+		if l := v.findLine(line, strings.Split(tmpl, "\n")[0]); l > 0 {
+			line = l
+		}
+		v.errorf(line, "%s: %s: invalid template: %v", step, field, err)
+	}
+}
+
+// input walks through the command input and compiles every string value
+func (v *flowValidator) input(line int, step, field string, input interface{}) {
+	b, err := json.Marshal(input)
+	if err != nil {
+		v.errorf(line, "%s: %s: %v", step, field, err)
+		return
+	}
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		v.errorf(line, "%s: %s: %v", step, field, err)
+		return
+	}
+	v.walkInput(line, step, field, value)
+}
+
+func (v *flowValidator) walkInput(line int, step, field string, value interface{}) {
+	switch t := value.(type) {
+	case string:
+		v.template(line, step, field, t)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v.walkInput(line, step, field+"."+k, t[k])
+		}
+	case []interface{}:
+		for i, e := range t {
+			v.walkInput(line, step, fmt.Sprintf("%s[%d]", field, i), e)
+		}
+	}
+}
+
+// findLine returns number of the first line starting from the line `from`
+// which contains all the substrings or 0 if there is no such line
+func (v *flowValidator) findLine(from int, substrs ...string) int {
+	if from < 1 {
+		from = 1
+	}
+	for i := from - 1; i < len(v.lines); i++ {
+		if containsAll(v.lines[i], substrs) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsAll(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
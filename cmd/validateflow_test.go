@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HotelsDotCom/flyte/flytepath"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFlow_ShouldPassForValidFlow(t *testing.T) {
+	output, err := executeCommand("validate", "flow", "-f", "testdata/valid-flow.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "flow \"status-flow\" is valid\n", output)
+}
+
+func TestValidateFlow_ShouldReportAllErrorsWithLineNumbers(t *testing.T) {
+	output, err := executeCommand("validate", "flow", "-f", "testdata/invalid-flow.yaml")
+
+	require.Error(t, err)
+	assert.Equal(t, "invalid flow: 7 error(s) found", err.Error())
+
+	lines := []string{
+		"testdata/invalid-flow.yaml: flow name is required",
+		"testdata/invalid-flow.yaml:8: step \"status\": criteria: invalid template:",
+		"testdata/invalid-flow.yaml:13: step \"status\": command.input.message: invalid template:",
+		"testdata/invalid-flow.yaml:14: step \"status\": duplicate step id, already used by step[0]",
+		"testdata/invalid-flow.yaml:14: step \"status\": event.packName is required",
+		"testdata/invalid-flow.yaml:14: step \"status\": command.name is required",
+		"testdata/invalid-flow.yaml:16: step[1]: dependsOn refers to unknown step \"unknown\"",
+	}
+	for _, l := range lines {
+		assert.Contains(t, output, l)
+	}
+}
+
+func TestValidateFlow_ShouldReportJsonSyntaxErrorWithLineNumber(t *testing.T) {
+	output, err := executeCommand("validate", "flow", "-f", "testdata/invalid-flow.json")
+
+	require.Error(t, err)
+	assert.Contains(t, output, "testdata/invalid-flow.json:4: cannot parse flow: invalid character '}'")
+}
+
+func TestValidateFlow_ShouldFailForNonJsonOrYamlFile(t *testing.T) {
+	output, err := executeCommand("validate", "flow", "-f", "testdata/my-flow.haha")
+
+	require.Error(t, err)
+	assert.Contains(t, output, "testdata/my-flow.haha: cannot parse flow: unsupported file type it must be JSON or YAML")
+}
+
+func TestValidateFlow_ShouldPassRemoteValidationWhenPacksDeclareEventsAndCommands(t *testing.T) {
+	ts := newPacksServer(`{"id":"Slack","name":"Slack","events":[{"name":"ReceivedMessage"},{"name":"MessageSent"}],"commands":[{"name":"SendMessage"}]}`, nil)
+	defer ts.Close()
+
+	output, err := executeCommand("validate", "flow", "-f", "testdata/valid-flow.yaml", "--remote", "--url", ts.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "flow \"status-flow\" is valid\n", output)
+}
+
+func TestValidateFlow_ShouldReportEventsAndCommandsNotDeclaredByPacks(t *testing.T) {
+	ts := newPacksServer(`{"id":"Slack","name":"Slack","labels":{"env":"prod"},"events":[{"name":"ReceivedMessage"}],"commands":[]}`, nil)
+	defer ts.Close()
+
+	output, err := executeCommand("validate", "flow", "-f", "testdata/remote-flow.yaml", "--remote", "--url", ts.URL)
+
+	require.Error(t, err)
+	assert.Equal(t, "invalid flow: 5 error(s) found", err.Error())
+	assert.Contains(t, output, "testdata/remote-flow.yaml:4: step \"status\": command \"SendMessage\" is not declared by live \"Slack\" pack\n")
+	assert.Contains(t, output, "testdata/remote-flow.yaml:13: step \"other\": event packLabels env=dev match no registered \"Slack\" pack\n")
+	assert.Contains(t, output, "testdata/remote-flow.yaml:13: step \"other\": command pack \"Shell\" is not registered\n")
+	assert.Contains(t, output, "testdata/remote-flow.yaml:24: step \"unknown\": event \"Unknown\" is not declared by live \"Slack\" pack\n")
+	assert.Contains(t, output, "testdata/remote-flow.yaml:24: step \"unknown\": command \"SendMessage\" is not declared by live \"Slack\" pack\n")
+}
+
+func TestValidateFlow_ShouldReportPacksWhichAreNotLive(t *testing.T) {
+	ts := newPacksServer(`{"id":"Slack","name":"Slack","status":"warning","events":[{"name":"ReceivedMessage"},{"name":"MessageSent"}],"commands":[{"name":"SendMessage"}]}`, nil)
+	defer ts.Close()
+
+	output, err := executeCommand("validate", "flow", "-f", "testdata/valid-flow.yaml", "--remote", "--url", ts.URL)
+
+	require.Error(t, err)
+	assert.Contains(t, output, "step \"status\": event pack \"Slack\" is not live, its status is warning\n")
+	assert.Contains(t, output, "step \"status\": command pack \"Slack\" is not live, its status is warning\n")
+}
+
+func TestValidateFlow_ShouldFailRemoteValidationWhenPacksCannotBeFetched(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	_, err := executeCommand("validate", "flow", "-f", "testdata/valid-flow.yaml", "--remote", "--url", ts.URL)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot list packs\nHTTP/1.1 500 Internal Server Error")
+}
+
+// newPacksServer serves Slack pack with the details and passes other requests to the handler
+func newPacksServer(slackPack string, handler http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == flytepath.PacksPath:
+			fmt.Fprint(w, `{"packs":[{"id":"Slack","name":"Slack","labels":{"env":"prod"}}]}`)
+		case r.Method == http.MethodGet && r.URL.Path == flytepath.PacksPath+"/Slack":
+			fmt.Fprint(w, slackPack)
+		case handler != nil:
+			handler(w, r)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
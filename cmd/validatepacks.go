@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// packStatusLive is the status of a pack which is polling flyte API for its commands
+const packStatusLive = "live"
+
+// validateFlowPacks checks that events and commands used by the flow steps
+// are declared by packs registered in a flyte API
+func validateFlowPacks(apiURL string, data []byte, flow *flowDef) ([]validationError, error) {
+	packs, err := fetchFlowPacks(apiURL, flow)
+	if err != nil {
+		return nil, err
+	}
+
+	v := flowValidator{lines: strings.Split(string(data), "\n")}
+	lines := v.stepLines(flow)
+	for i, s := range flow.Steps {
+		name := stepName(i, s)
+		v.packRef(lines[i], name, "event", packs, s.Event.PackName, s.Event.PackLabels, s.Event.Name, packEvents)
+		v.packRef(lines[i], name, "command", packs, s.Command.PackName, s.Command.PackLabels, s.Command.Name, packCommands)
+	}
+	return v.errs, nil
+}
+
+// fetchFlowPacks gets details of the registered packs referenced by the flow
+func fetchFlowPacks(apiURL string, flow *flowDef) ([]pack, error) {
+	list, err := listPacks(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	for _, s := range flow.Steps {
+		used[s.Event.PackName] = true
+		used[s.Command.PackName] = true
+	}
+
+	var packs []pack
+	for _, p := range list {
+		if !used[p.Name] {
+			continue
+		}
+		var details pack
+		if err := getJSON(packURL(apiURL, p.ID), &details); err != nil {
+			return nil, fmt.Errorf("cannot get pack %s\n%s", p.ID, err)
+		}
+		if details.Status == "" {
+			details.Status = p.Status
+		}
+		packs = append(packs, details)
+	}
+	return packs, nil
+}
+
+// packRef checks that there is a live registered pack with the name and labels which declares the event or command.
+// Packs without status are treated as live, the status is not reported by older flyte APIs.
+func (v *flowValidator) packRef(line int, step, kind string, packs []pack, packName string, packLabels map[string]string, name string, declared func(pack) []string) {
+	if packName == "" || name == "" {
+		return
+	}
+
+	var matching []pack
+	named := false
+	for _, p := range packs {
+		if p.Name != packName {
+			continue
+		}
+		named = true
+		if labelsMatch(packLabels, p.Labels) {
+			matching = append(matching, p)
+		}
+	}
+
+	if !named {
+		v.errorf(line, "%s: %s pack %q is not registered", step, kind, packName)
+		return
+	}
+	if len(matching) == 0 {
+		v.errorf(line, "%s: %s packLabels %s match no registered %q pack", step, kind, formatLabels(packLabels), packName)
+		return
+	}
+
+	var live []pack
+	var statuses []string
+	for _, p := range matching {
+		if p.Status == "" || p.Status == packStatusLive {
+			live = append(live, p)
+		} else {
+			statuses = append(statuses, p.Status)
+		}
+	}
+	if len(live) == 0 {
+		sort.Strings(statuses)
+		v.errorf(line, "%s: %s pack %q is not live, its status is %s", step, kind, packName, strings.Join(statuses, ", "))
+		return
+	}
+
+	for _, p := range live {
+		for _, n := range declared(p) {
+			if n == name {
+				return
+			}
+		}
+	}
+	v.errorf(line, "%s: %s %q is not declared by live %q pack", step, kind, name, packName)
+}
+
+// labelsMatch returns true if all wanted labels are present in pack labels
+func labelsMatch(want, labels map[string]string) bool {
+	for k, v := range want {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+func packEvents(p pack) []string {
+	names := make([]string, len(p.Events))
+	for i, e := range p.Events {
+		names[i] = e.Name
+	}
+	return names
+}
+
+func packCommands(p pack) []string {
+	names := make([]string, len(p.Commands))
+	for i, c := range p.Commands {
+		names[i] = c.Name
+	}
+	return names
+}
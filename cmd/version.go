@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 const (
@@ -16,8 +15,8 @@ func newCmdVersion() *cobra.Command {
 		Use:   "version",
 		Short: "Show the flyte cli version information",
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("Client version:\t%s\nAPI version:\t%s\nAPI URL:\t%s\n",
-				cliVersion, apiVersion, viper.GetString(flagURL))
+			fmt.Fprintf(cmd.OutOrStdout(), "Client version:\t%s\nAPI version:\t%s\nAPI URL:\t%s\nContext:\t%s\n",
+				cliVersion, apiVersion, apiURL(), activeContext.Name)
 		},
 	}
 	return cmd